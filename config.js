@@ -3,6 +3,12 @@ window.__APP_CONFIG__ = {
     MARKER_CLUSTER_THRESHOLD: 500,
     HEATMAP_THRESHOLD: 500,
     MARKER_BATCH_SIZE: 200,
+    DROP_GPS_GLITCHES: false,
+    COUNTRY_SCHEME: 'boundaries',
+    COUNTRY_ALIASES: {},
+    HIDDEN_STATS: [],
+    LOCALE: null,
+    RECAP_RANGES: [],
     SHARE_IMAGE_WIDTH: 1080,
     SHARE_IMAGE_HEIGHT: 1920,
     DATA_DEMO_URL: 'data/demo.json',
@@ -4,6 +4,7 @@ let markers = null;
 let heatLayer = null; // Used when location count exceeds HEATMAP_THRESHOLD (fast canvas render)
 let currentStyle = 'light';
 let selectedYear = null;
+let selectedRange = null; // Custom recap range { name, from, to } (inclusive YYYY-MM-DD); takes precedence over selectedYear
 let allLocations = []; // Store loaded locations for filtering
 let allSegments = []; // Store all timeline segments (visits and activities)
 let globe = null; // Globe instance
@@ -20,7 +21,8 @@ let lastAllTimeAdvancedStats = null;
 // Stats cache: avoid recomputing on every render/year switch
 let cachedAllTimeStats = null;
 let cachedAllTimeAdvancedStats = null;
-let statsCacheByYear = {}; // key: String(year) or 'all'; value: { stats, advancedStats, statsSegments }
+let cachedLifetimeMilestones = null;
+let statsCacheByYear = {}; // key: String(year) or 'all'; value: { stats, advancedStats, periodStats, extremes, repeatVisits, revisits, coverageGaps, countryHistory, statsSegments }
 
 // CartoDB Tile Layer URLs
 const tileLayers = {
@@ -79,9 +81,96 @@ const APP_CONFIG = (typeof window !== 'undefined' && window.__APP_CONFIG__) || {
 function getConfig(key, fallback) {
     return APP_CONFIG[key] !== undefined && APP_CONFIG[key] !== null ? APP_CONFIG[key] : fallback;
 }
+// BCP 47 locale for numbers and dates (e.g. 'de-DE' -> "12.345 km"); undefined = browser default
+const LOCALE = (() => {
+    const locale = getConfig('LOCALE', undefined);
+    try {
+        return locale ? Intl.getCanonicalLocales(locale)[0] : undefined;
+    } catch (e) {
+        timelineUtils.Logger.warn(`Invalid LOCALE "${locale}" in config.js, using browser default`);
+        return undefined;
+    }
+})();
 const MARKER_CLUSTER_THRESHOLD = getConfig('MARKER_CLUSTER_THRESHOLD', 500);
 const HEATMAP_THRESHOLD = getConfig('HEATMAP_THRESHOLD', 500);
 const MARKER_BATCH_SIZE = getConfig('MARKER_BATCH_SIZE', 200);
+const DROP_GPS_GLITCHES = getConfig('DROP_GPS_GLITCHES', false);
+const HIDDEN_STATS = new Set(getConfig('HIDDEN_STATS', []));
+// Named recap ranges shown after the years, e.g. { name: 'Gap year 2023–24', from: '2023-09-01', to: '2024-08-31' }
+const RECAP_RANGES = getConfig('RECAP_RANGES', []).filter(range => {
+    const isDate = d => typeof d === 'string' && /^\d{4}-\d{2}-\d{2}$/.test(d);
+    if (range && isDate(range.from) && isDate(range.to) && range.from <= range.to) return true;
+    timelineUtils.Logger.warn('Ignoring invalid RECAP_RANGES entry in config.js', range);
+    return false;
+});
+
+// Dashboard elements showing each stat that HIDDEN_STATS can hide. Values mixed into other text
+// (travel summary, lifetime stats, seasons) are left out by their render functions via isStatHidden.
+const HIDEABLE_STAT_ELEMENTS = {
+    distance: ['story-distance', 'world-percentage', 'world-percentage-label'],
+    places: ['stat-places'],
+    countries: ['stat-countries-text', 'stat-world-percent', 'stat-repeat-visits', 'story-countries'],
+    visits: ['story-visits'],
+    time: ['story-time'],
+    seasons: ['story-seasons'],
+    transport: ['story-transport'],
+    topPlaces: ['story-top-places'],
+    records: ['story-records'],
+    eco: ['story-eco']
+};
+
+function isStatHidden(key) {
+    return HIDDEN_STATS.has(key);
+}
+
+// Hide dashboard elements for opted-out stats (run after rendering, which may unhide sections)
+function applyHiddenStats() {
+    HIDDEN_STATS.forEach(key => {
+        (HIDEABLE_STAT_ELEMENTS[key] || []).forEach(id => {
+            const el = document.getElementById(id);
+            if (el) el.classList.add('hidden');
+        });
+    });
+    // Places section holds both the places and the countries headline
+    if (isStatHidden('places') && isStatHidden('countries')) {
+        const placesSection = document.getElementById('story-places');
+        if (placesSection) placesSection.classList.add('hidden');
+    }
+}
+
+const COUNTRY_SCHEME = getConfig('COUNTRY_SCHEME', 'boundaries');
+const COUNTRY_ALIASES = getConfig('COUNTRY_ALIASES', {});
+if (window.timelineUtils && typeof timelineUtils.setCountryScheme === 'function') {
+    timelineUtils.setCountryScheme(COUNTRY_SCHEME, COUNTRY_ALIASES);
+}
+
+// Report segments whose timestamps were derived (see backfillSegmentTimes) or are missing entirely
+function logTimeBackfill(timeBackfill) {
+    if (!timeBackfill) return;
+    if (timeBackfill.derived > 0) {
+        timelineUtils.Logger.info(`Derived missing timestamps for ${timeBackfill.derived} segment(s); they are left out of speeds, records and the date range`);
+    }
+    if (timeBackfill.untimed > 0) {
+        timelineUtils.Logger.warn(`${timeBackfill.untimed} segment(s) have no usable timestamp: counted in all-time stats only`);
+    }
+}
+
+// Report activities too fast for their mode (kept out of speed stats) so they can be cleaned up
+function logSpeedGlitches(advancedStats) {
+    const glitches = advancedStats.speedGlitches;
+    if (!glitches || glitches.length === 0) return;
+    timelineUtils.Logger.warn(`Found ${glitches.length} activity segment(s) with an impossible speed for their mode – excluded from speed stats`, glitches);
+}
+
+// Report teleporting GPS points and, if configured, drop them from the map locations
+function filterGpsGlitches(locations) {
+    const glitches = timelineUtils.detectLocationGlitches(locations);
+    if (glitches.length === 0) return locations;
+    timelineUtils.Logger.warn(`Found ${glitches.length} GPS glitch point(s)${DROP_GPS_GLITCHES ? ' – dropped from map' : ' – set DROP_GPS_GLITCHES in config.js to drop them'}`, glitches);
+    if (!DROP_GPS_GLITCHES) return locations;
+    const glitchIndices = new Set(glitches.map(g => g.index));
+    return locations.filter((_, i) => !glitchIndices.has(i));
+}
 
 // Initialize map
 function initMap() {
@@ -189,8 +278,20 @@ function initializeYearFilter(availableYears) {
         timelineContainer.appendChild(yearBtn);
     });
 
+    // Named ranges from config, after the years
+    RECAP_RANGES.forEach((range, index) => {
+        const rangeBtn = document.createElement('button');
+        rangeBtn.className = 'timeline-year-btn';
+        rangeBtn.dataset.range = index;
+        rangeBtn.textContent = formatRangeLabel(range);
+        rangeBtn.addEventListener('click', () => selectRecapRange(range));
+        timelineContainer.appendChild(rangeBtn);
+    });
+
+    initializeRangeInputs(availableYears);
+
     // If we have years and no selected year (or invalid one), select the most recent one
-    if (availableYears.length > 0 && (!selectedYear || !availableYears.includes(parseInt(selectedYear)))) {
+    if (availableYears.length > 0 && !selectedRange && (!selectedYear || !availableYears.includes(parseInt(selectedYear)))) {
         selectedYear = availableYears[availableYears.length - 1]; // Most recent (last in ascending order)
         localStorage.setItem('mapYear', selectedYear);
         // Update title to reflect year
@@ -213,9 +314,11 @@ function initializeYearFilter(availableYears) {
         shell.addEventListener('scroll', () => {
             clearTimeout(scrollEndTimer);
             scrollEndTimer = setTimeout(() => {
+                // A custom from/to range has no button; scrolling (e.g. to reach the inputs) keeps it
+                if (selectedRange && !RECAP_RANGES.includes(selectedRange)) return;
                 const shellRect = shell.getBoundingClientRect();
                 const centerX = shellRect.left + shellRect.width / 2;
-                const buttons = shell.querySelectorAll('.timeline-year-btn');
+                const buttons = timelineContainer.querySelectorAll('.timeline-year-btn');
                 let closest = null;
                 let closestDist = Infinity;
                 buttons.forEach((btn) => {
@@ -227,17 +330,82 @@ function initializeYearFilter(availableYears) {
                         closest = btn;
                     }
                 });
-                if (closest && closest.dataset.year !== (selectedYear || '')) {
-                    selectTimelineYear(closest.dataset.year);
+                if (closest && !closest.classList.contains('active')) {
+                    closest.click();
                 }
             }, 120);
         }, { passive: true });
     }
 }
 
+// Custom from/to date inputs next to the year timeline (bounded by the years in the data)
+function initializeRangeInputs(availableYears) {
+    const rangeContainer = document.getElementById('timeline-range-container');
+    const fromInput = document.getElementById('range-from');
+    const toInput = document.getElementById('range-to');
+    if (!rangeContainer || !fromInput || !toInput) return;
+
+    rangeContainer.classList.toggle('hidden', availableYears.length === 0);
+    if (availableYears.length === 0) return;
+    const minDate = `${Math.min(...availableYears)}-01-01`;
+    const maxDate = `${Math.max(...availableYears)}-12-31`;
+    [fromInput, toInput].forEach(input => {
+        input.min = minDate;
+        input.max = maxDate;
+    });
+
+    if (rangeContainer.dataset.listenersAttached) return;
+    rangeContainer.dataset.listenersAttached = '1';
+    const onRangeInput = () => {
+        if (fromInput.value && toInput.value && fromInput.value <= toInput.value) {
+            selectRecapRange({ name: null, from: fromInput.value, to: toInput.value });
+        }
+    };
+    fromInput.addEventListener('change', onRangeInput);
+    toInput.addEventListener('change', onRangeInput);
+}
+
+function formatRangeLabel(range) {
+    if (range.name) return range.name;
+    const formatDate = (key) => {
+        const [year, month, day] = key.split('-').map(Number);
+        return new Date(year, month - 1, day).toLocaleDateString(LOCALE, { month: 'short', day: 'numeric', year: 'numeric' });
+    };
+    return `${formatDate(range.from)} – ${formatDate(range.to)}`;
+}
+
+// Label for the selected recap period (range or year); null for all years
+function getSelectedPeriodLabel() {
+    if (selectedRange) return formatRangeLabel(selectedRange);
+    return selectedYear ? String(selectedYear) : null;
+}
+
+// Whether a timestamp falls in the selected recap period, by its local calendar date
+function isInSelectedPeriod(isoString) {
+    if (selectedRange) return timelineUtils.isInLocalDateRange(isoString, selectedRange.from, selectedRange.to);
+    if (!selectedYear) return true;
+    const localDate = timelineUtils.getLocalDateParts(isoString);
+    return localDate !== null && localDate.year === parseInt(selectedYear);
+}
+
+// Select a saved or custom date range instead of a year
+function selectRecapRange(range) {
+    selectedRange = range;
+    updateTimelineSelection();
+    const label = getSelectedPeriodLabel();
+    document.getElementById('header-title').textContent = `Your ${label} Recap`;
+
+    showLoadingScreen(`Loading ${label}…`);
+    setTimeout(() => {
+        renderDashboard();
+        hideLoadingScreen();
+    }, 0);
+}
+
 // Select a year from the timeline
 function selectTimelineYear(year) {
     selectedYear = year === '' ? null : year;
+    selectedRange = null;
     localStorage.setItem('mapYear', year);
     updateTimelineSelection();
     
@@ -270,17 +438,23 @@ function updateTimelineSelection() {
     const timelineContainer = document.getElementById('timeline-years');
     if (!timelineContainer) return;
     
-    // Update all year buttons
+    // Update all year and saved range buttons (a custom from/to range leaves none active)
     const yearBtns = timelineContainer.querySelectorAll('.timeline-year-btn');
     yearBtns.forEach(btn => {
-        const btnYear = btn.dataset.year;
-        if (btnYear === (selectedYear || '')) {
-            btn.classList.add('active');
-        } else {
-            btn.classList.remove('active');
-        }
+        const isActive = btn.dataset.range !== undefined
+            ? selectedRange === RECAP_RANGES[btn.dataset.range]
+            : !selectedRange && btn.dataset.year === (selectedYear || '');
+        btn.classList.toggle('active', isActive);
     });
 
+    // Clear the custom range inputs unless a custom range is selected
+    if (!selectedRange || RECAP_RANGES.includes(selectedRange)) {
+        const fromInput = document.getElementById('range-from');
+        const toInput = document.getElementById('range-to');
+        if (fromInput) fromInput.value = '';
+        if (toInput) toInput.value = '';
+    }
+
     // Move the sliding knob to the active button
     const activeBtn = timelineContainer.querySelector('.timeline-year-btn.active');
     const knob = timelineContainer.querySelector('.timeline-switch-knob');
@@ -289,6 +463,8 @@ function updateTimelineSelection() {
         const width = activeBtn.offsetWidth;
         knob.style.width = `${width}px`;
         knob.style.transform = `translateX(${left}px)`;
+    } else if (knob) {
+        knob.style.width = '0px';
     }
 
     // Keep selected year centered in the timeline bar
@@ -500,7 +676,11 @@ function handleFileUpload(event) {
                     worker.onerror = function () {
                         runSync();
                     };
-                    worker.postMessage({ jsonText, countryGeoJSON: countryGeoJSONCache || null });
+                    worker.postMessage({
+                        jsonText,
+                        countryGeoJSON: countryGeoJSONCache || null,
+                        countryScheme: { name: COUNTRY_SCHEME, aliases: COUNTRY_ALIASES }
+                    });
                 } catch (err) {
                     runSync();
                 }
@@ -545,16 +725,20 @@ function loadDemoData() {
 
 // Apply Worker result (allSegments, allLocations, years, initialStats) and update UI – same as processAndRenderData but without parse/process
 function applyProcessedDataFromWorker(payload) {
-    const { allSegments: segs, allLocations: locs, years, initialStats } = payload;
+    const { allSegments: segs, allLocations: locs, years, initialStats, timeBackfill } = payload;
     allSegments = segs;
-    allLocations = locs;
+    allLocations = filterGpsGlitches(locs);
+    logTimeBackfill(timeBackfill);
     mapYears = [...years];
     isDataLoaded = true;
 
     // Populate all-time stats cache and clear per-year cache
     cachedAllTimeStats = timelineUtils.calculateStats(allSegments);
     cachedAllTimeAdvancedStats = timelineUtils.calculateAdvancedStats(allSegments);
+    logSpeedGlitches(cachedAllTimeAdvancedStats);
+    cachedLifetimeMilestones = null;
     statsCacheByYear = {};
+    selectedRange = null;
 
     timelineUtils.Logger.info(`Parsed ${allLocations.length} locations`);
 
@@ -602,7 +786,8 @@ function processAndRenderData(json) {
     const processed = timelineUtils.processTimelineData(json);
 
     allSegments = processed.allSegments;
-    allLocations = processed.allLocations;
+    allLocations = filterGpsGlitches(processed.allLocations);
+    logTimeBackfill(processed.timeBackfill);
     const years = processed.years;
     mapYears = [...years];
     isDataLoaded = true;
@@ -611,7 +796,10 @@ function processAndRenderData(json) {
     const initialStats = timelineUtils.calculateStats(allSegments);
     cachedAllTimeStats = initialStats;
     cachedAllTimeAdvancedStats = timelineUtils.calculateAdvancedStats(allSegments);
+    logSpeedGlitches(cachedAllTimeAdvancedStats);
+    cachedLifetimeMilestones = null;
     statsCacheByYear = {};
+    selectedRange = null;
 
     timelineUtils.Logger.info(`Parsed ${allLocations.length} locations`);
     timelineUtils.Logger.timeEnd('Data Processing');
@@ -653,38 +841,45 @@ function processAndRenderData(json) {
     }
 }
 
-// Main render function that updates all sections based on selectedYear
+// Main render function that updates all sections based on selectedYear (or selectedRange)
 function renderDashboard() {
     // 1. Render Map
     renderMarkers();
 
     // 2. Resolve stats from cache or compute
-    const currentYear = selectedYear ? parseInt(selectedYear) : null;
-    const cacheKey = currentYear === null ? 'all' : String(currentYear);
-    let stats, advancedStats, statsSegments;
+    const currentYear = selectedYear && !selectedRange ? parseInt(selectedYear) : null;
+    const cacheKey = selectedRange ? `${selectedRange.from}..${selectedRange.to}` : (currentYear === null ? 'all' : String(currentYear));
+    let stats, advancedStats, periodStats, extremes, repeatVisits, revisits, coverageGaps, countryHistory, statsSegments;
 
     if (statsCacheByYear[cacheKey]) {
         const cached = statsCacheByYear[cacheKey];
         stats = cached.stats;
         advancedStats = cached.advancedStats;
+        periodStats = cached.periodStats;
+        extremes = cached.extremes;
+        repeatVisits = cached.repeatVisits;
+        revisits = cached.revisits;
+        coverageGaps = cached.coverageGaps;
+        countryHistory = cached.countryHistory;
         statsSegments = cached.statsSegments;
     } else {
-        statsSegments = currentYear
-            ? allSegments.filter(s => {
-                if (!s.startTime) return false;
-                return new Date(s.startTime).getFullYear() === currentYear;
-            })
+        statsSegments = currentYear || selectedRange
+            ? allSegments.filter(s => isInSelectedPeriod(s.startTime))
             : allSegments;
         stats = timelineUtils.calculateStats(statsSegments);
         advancedStats = timelineUtils.calculateAdvancedStats(statsSegments);
-        statsCacheByYear[cacheKey] = { stats, advancedStats, statsSegments };
+        periodStats = timelineUtils.calculatePeriodBreakdown(statsSegments, allSegments);
+        extremes = timelineUtils.calculateExtremes(statsSegments);
+        // Year-over-year comparisons: only for calendar years
+        repeatVisits = currentYear ? timelineUtils.calculateRepeatVisits(allSegments, currentYear) : null;
+        revisits = currentYear ? timelineUtils.calculateRevisits(allSegments, currentYear) : {};
+        coverageGaps = timelineUtils.findCoverageGaps(statsSegments);
+        countryHistory = timelineUtils.calculateCountryHistory(statsSegments);
+        statsCacheByYear[cacheKey] = { stats, advancedStats, periodStats, extremes, repeatVisits, revisits, coverageGaps, countryHistory, statsSegments };
     }
 
-    if (currentYear) {
-        document.getElementById('header-title').textContent = `Your ${currentYear} Recap`;
-    } else {
-        document.getElementById('header-title').textContent = `Your Travel Recap`;
-    }
+    const periodLabel = getSelectedPeriodLabel();
+    document.getElementById('header-title').textContent = periodLabel ? `Your ${periodLabel} Recap` : 'Your Travel Recap';
 
     const allTimeStats = cachedAllTimeStats !== null ? cachedAllTimeStats : timelineUtils.calculateStats(allSegments);
     const allTimeAdvancedStats = cachedAllTimeAdvancedStats !== null ? cachedAllTimeAdvancedStats : timelineUtils.calculateAdvancedStats(allSegments);
@@ -696,20 +891,30 @@ function renderDashboard() {
 
     // 4. Update UI Sections
     renderStatistics(stats);
+    renderRepeatVisits(repeatVisits);
     renderTravelSummary(stats);
     renderTravelTrends(stats.transport);
     renderVisitTrends(stats.visits);
     renderHighlights(stats.visits, statsSegments);
     renderAllTimeStats(allTimeStats);
+    if (cachedLifetimeMilestones === null) {
+        cachedLifetimeMilestones = timelineUtils.calculateLifetimeMilestones(allSegments);
+    }
+    renderLifetimeMilestones(cachedLifetimeMilestones);
 
     // 5. Render New Metrics
     renderEcoImpact(advancedStats.eco);
     renderTimeDistribution(advancedStats.time);
-    renderRecordBreakers(advancedStats.records, statsSegments);
+    renderCoverageGaps(coverageGaps);
+    renderRecordBreakers(advancedStats.records, statsSegments, advancedStats.speeds);
+    renderExtremes(extremes);
+    renderSeasonBreakdown(periodStats);
     
     // 6. Render Transport Breakdown and Top Places (new typography sections)
     renderTransportBreakdown(stats.transport);
-    renderTopPlacesSection(stats.visits);
+    renderTopPlacesSection(stats.visits, revisits);
+    renderCountryHistory(countryHistory);
+    applyHiddenStats();
 
     // 6. Reveal Dashboard
     const dashboard = document.getElementById('dashboard-content');
@@ -737,13 +942,13 @@ function renderEcoImpact(ecoStats) {
     // Eco CO2 story element
     const ecoCo2El = document.getElementById('stat-eco-co2');
     if (ecoCo2El) {
-        ecoCo2El.textContent = `${savedKg.toLocaleString()} kg CO₂ saved`;
+        ecoCo2El.textContent = `${savedKg.toLocaleString(LOCALE)} kg CO₂ saved`;
     }
     
     // Trees count element
     const treesCountEl = document.getElementById('stat-trees-count');
     if (treesCountEl) {
-        treesCountEl.textContent = treesNeeded.toLocaleString();
+        treesCountEl.textContent = treesNeeded.toLocaleString(LOCALE);
     }
     
     // Keep backward compatibility with hidden grid
@@ -762,11 +967,11 @@ function renderTimeDistribution(timeStats) {
         if (days >= 1) {
             // Show days if >= 1 day
             const roundedDays = Math.round(days * 10) / 10; // 1 decimal place
-            return { value: roundedDays, unit: roundedDays === 1 ? 'day' : 'days' };
+            return { value: roundedDays.toLocaleString(LOCALE), unit: roundedDays === 1 ? 'day' : 'days' };
         } else {
             // Show hours if < 1 day
             const roundedHours = Math.round(hours);
-            return { value: roundedHours, unit: roundedHours === 1 ? 'hour' : 'hours' };
+            return { value: roundedHours.toLocaleString(LOCALE), unit: roundedHours === 1 ? 'hour' : 'hours' };
         }
     }
     
@@ -800,16 +1005,32 @@ function renderTimeDistribution(timeStats) {
     }
 }
 
-function renderRecordBreakers(records, segments) {
+function renderCoverageGaps(gaps) {
+    const el = document.getElementById('stat-coverage-gaps');
+    if (!el) return;
+    if (gaps.length === 0) {
+        el.textContent = '';
+        return;
+    }
+    const formatDate = (iso) => formatDisplayDate(iso, { month: 'short', day: 'numeric' });
+    // Show the longest gaps, in date order
+    const longest = [...gaps].sort((a, b) => b.days - a.days).slice(0, 3)
+        .sort((a, b) => new Date(a.start) - new Date(b.start));
+    const ranges = longest.map(g => `${formatDate(g.start)} – ${formatDate(g.end)}`).join(', ');
+    const more = gaps.length > longest.length ? ` and ${gaps.length - longest.length} more` : '';
+    el.textContent = `No location data for ${ranges}${more}`;
+}
+
+function renderRecordBreakers(records, segments, speeds = {}) {
     // Calculate records with dates from segments
     let longestDriveRecord = { distance: 0, date: null };
     let longestWalkRecord = { distance: 0, date: null };
     
     segments.forEach(segment => {
-        if (segment.activity && segment.activity.distanceMeters) {
+        if (segment.activity && segment.activity.distanceMeters && !segment.timeDerived) {
             const type = segment.activity.topCandidate?.type || 'UNKNOWN';
             const distance = segment.activity.distanceMeters;
-            const date = segment.startTime ? new Date(segment.startTime) : null;
+            const date = segment.startTime || null;
             
             if ((type === 'IN_PASSENGER_VEHICLE' || type === 'IN_VEHICLE') && distance > longestDriveRecord.distance) {
                 longestDriveRecord = { distance, date };
@@ -823,12 +1044,12 @@ function renderRecordBreakers(records, segments) {
     // Format date helper
     const formatDate = (date) => {
         if (!date) return '';
-        return date.toLocaleDateString('en-US', { month: 'short', day: 'numeric', year: 'numeric' });
+        return formatDisplayDate(date, { month: 'short', day: 'numeric', year: 'numeric' });
     };
     
     // Update Typography Story Section for Records
-    const driveKm = (longestDriveRecord.distance / 1000).toFixed(1);
-    const walkKm = (longestWalkRecord.distance / 1000).toFixed(1);
+    const driveKm = formatDecimal(longestDriveRecord.distance / 1000, 1);
+    const walkKm = formatDecimal(longestWalkRecord.distance / 1000, 1);
     
     // Longest drive story element
     const longestDriveEl = document.getElementById('stat-longest-drive');
@@ -850,6 +1071,24 @@ function renderRecordBreakers(records, segments) {
         longestWalkDateEl.textContent = formatDate(longestWalkRecord.date);
     }
     
+    // Top speed story element (GPS glitches already excluded by calculateAdvancedStats)
+    const topSpeedEl = document.getElementById('stat-top-speed');
+    if (topSpeedEl) {
+        const fastest = Object.entries(speeds).sort(([, a], [, b]) => b.maxKmh - a.maxKmh)[0];
+        if (records.maxVelocity > 0 && fastest) {
+            const label = (transportConfig[fastest[0]] || transportConfig['UNKNOWN']).label;
+            // Average speeds for the most-used modes
+            const averages = Object.entries(speeds)
+                .sort(([, a], [, b]) => b.count - a.count)
+                .slice(0, 3)
+                .map(([type, s]) => `${(transportConfig[type] || transportConfig['UNKNOWN']).label} ${Math.round(s.avgKmh).toLocaleString(LOCALE)} km/h`);
+            topSpeedEl.innerHTML = `Top speed: <strong>${Math.round(records.maxVelocity).toLocaleString(LOCALE)} km/h</strong> (${label})`
+                + `<br>Average: ${averages.join(' · ')}`;
+        } else {
+            topSpeedEl.textContent = '';
+        }
+    }
+
     // Keep backward compatibility with hidden container
     const container = document.getElementById('record-breakers-stats');
     if (container) {
@@ -857,6 +1096,104 @@ function renderRecordBreakers(records, segments) {
     }
 }
 
+function renderExtremes(extremes) {
+    const grid = document.getElementById('stat-extremes-grid');
+    if (grid) {
+        grid.innerHTML = '';
+        const directions = [
+            { key: 'north', label: 'Furthest North', coord: p => `${formatDecimal(Math.abs(p.lat), 2)}°${p.lat >= 0 ? 'N' : 'S'}` },
+            { key: 'south', label: 'Furthest South', coord: p => `${formatDecimal(Math.abs(p.lat), 2)}°${p.lat >= 0 ? 'N' : 'S'}` },
+            { key: 'east', label: 'Furthest East', coord: p => `${formatDecimal(Math.abs(p.lng), 2)}°${p.lng >= 0 ? 'E' : 'W'}` },
+            { key: 'west', label: 'Furthest West', coord: p => `${formatDecimal(Math.abs(p.lng), 2)}°${p.lng >= 0 ? 'E' : 'W'}` }
+        ];
+        directions.forEach(({ key, label, coord }) => {
+            const point = extremes[key];
+            if (!point) return;
+            const place = point.name || (!isStatHidden('countries') && point.country) || coord(point);
+            const div = document.createElement('div');
+            div.innerHTML = `
+                <p class="text-sm text-gray-500 dark:text-gray-400 uppercase tracking-wider mb-2">${label}</p>
+                <h3 class="text-xl md:text-2xl font-black text-gray-900 dark:text-white truncate" title="${place}">${place}</h3>
+                <p class="text-sm text-gray-400 dark:text-gray-500 mt-1">${coord(point)}</p>
+            `;
+            grid.appendChild(div);
+        });
+    }
+
+    const rangeEl = document.getElementById('stat-date-range');
+    if (rangeEl) {
+        const formatDate = (iso) => formatDisplayDate(iso, { month: 'short', day: 'numeric', year: 'numeric' });
+        rangeEl.textContent = extremes.firstDate && extremes.lastDate
+            ? `From ${formatDate(extremes.firstDate)} to ${formatDate(extremes.lastDate)}`
+            : '';
+    }
+
+    const tripEl = document.getElementById('stat-longest-trip');
+    if (tripEl) {
+        const formatTripDate = (iso) => formatDisplayDate(iso, { month: 'short', day: 'numeric' });
+        const describeTrip = (trip) => {
+            const dates = trip.days === 1 ? formatTripDate(trip.start) : `${formatTripDate(trip.start)} – ${formatTripDate(trip.end)}`;
+            const place = isStatHidden('countries') ? `(${dates})` : `in ${trip.countries.map(formatCountryWithFlag).join(', ')} (${dates})`;
+            const topKmh = Math.max(0, ...Object.values(trip.speeds || {}).map(s => s.maxKmh));
+            return topKmh > 0 ? `${place}, top speed ${Math.round(topKmh).toLocaleString(LOCALE)} km/h` : place;
+        };
+        const formatKm = (trip) => `${Math.round(trip.distanceMeters / 1000).toLocaleString(LOCALE)} km`;
+        const showDistance = !isStatHidden('distance');
+        const byDays = extremes.longestTripByDays;
+        const byDistance = showDistance ? extremes.longestTripByDistance : null;
+        if (!byDays) {
+            tripEl.textContent = '';
+        } else {
+            const daysText = `${byDays.days.toLocaleString(LOCALE)} ${byDays.days === 1 ? 'day' : 'days'}`;
+            tripEl.innerHTML = byDays === byDistance
+                ? `Longest trip: <strong>${daysText}</strong> and <strong>${formatKm(byDays)}</strong> ${describeTrip(byDays)}`
+                : `Longest trip: <strong>${daysText}</strong> ${describeTrip(byDays)}`
+                    + (byDistance ? `<br>Furthest trip: <strong>${formatKm(byDistance)}</strong> ${describeTrip(byDistance)}` : '');
+        }
+    }
+}
+
+// Month names in the configured locale, indexed 0-11
+const MONTH_NAMES = (() => {
+    const format = new Intl.DateTimeFormat(LOCALE, { month: 'long', timeZone: 'UTC' });
+    return Array.from({ length: 12 }, (_, month) => format.format(Date.UTC(2000, month, 1)));
+})();
+
+function renderSeasonBreakdown(periodStats) {
+    const section = document.getElementById('story-seasons');
+    const seasonEl = document.getElementById('stat-top-season');
+    const monthEl = document.getElementById('stat-top-month');
+    if (!section || !seasonEl || !monthEl) return;
+
+    // Rank periods by distance; exports without activity distances (common on iOS) or with distance
+    // hidden fall back to visits
+    const seasons = Object.entries(periodStats.seasons);
+    const totalDistance = seasons.reduce((sum, [, s]) => sum + s.distanceMeters, 0);
+    const metric = totalDistance > 0 && !isStatHidden('distance') ? 'distanceMeters' : 'visits';
+    const total = metric === 'visits' && isStatHidden('visits') ? 0 : seasons.reduce((sum, [, s]) => sum + s[metric], 0);
+    if (total <= 0) {
+        section.classList.add('hidden');
+        return;
+    }
+    section.classList.remove('hidden');
+
+    const [topSeason, topSeasonStats] = seasons.sort(([, a], [, b]) => b[metric] - a[metric])[0];
+    const share = (topSeasonStats[metric] / total).toLocaleString(LOCALE, { style: 'percent', maximumFractionDigits: 0 });
+    seasonEl.textContent = `${topSeason} was ${share} of your ${metric === 'visits' ? 'visits' : 'travel'}`;
+
+    let topMonth = 0;
+    periodStats.months.forEach((m, i) => {
+        if (m[metric] > periodStats.months[topMonth][metric]) topMonth = i;
+    });
+    const monthStats = periodStats.months[topMonth];
+    const monthValue = metric === 'visits'
+        ? `${monthStats.visits.toLocaleString(LOCALE)} ${monthStats.visits === 1 ? 'visit' : 'visits'}`
+        : `${Math.round(monthStats.distanceMeters / 1000).toLocaleString(LOCALE)} km`;
+    const newCountries = isStatHidden('countries') ? 0 : monthStats.newCountries.length;
+    monthEl.innerHTML = `Your busiest month was <strong>${MONTH_NAMES[topMonth]}</strong> with <strong>${monthValue}</strong>`
+        + (newCountries > 0 ? ` and <strong>${newCountries.toLocaleString(LOCALE)}</strong> new ${newCountries === 1 ? 'country' : 'countries'}.` : '.');
+}
+
 // Transport labels mapping
 const transportConfig = {
     'IN_PASSENGER_VEHICLE': { label: 'Driving' },
@@ -902,21 +1239,35 @@ function renderTransportBreakdown(transportStats) {
         card.className = 'transport-stat-card';
         card.innerHTML = `
             <div class="transport-label">${config.label}</div>
-            <div class="transport-value">${distanceKm.toLocaleString()} km</div>
-            <div class="transport-sublabel">${data.count} trips • ${durationHours}h</div>
+            <div class="transport-value">${distanceKm.toLocaleString(LOCALE)} km</div>
+            <div class="transport-sublabel">${data.count.toLocaleString(LOCALE)} trips • ${durationHours.toLocaleString(LOCALE)}h</div>
         `;
         grid.appendChild(card);
     });
 }
 
-function renderTopPlacesSection(visitStats) {
+// "3 years ago" / "5 months ago" / "12 days ago"
+function formatTimeSince(days) {
+    if (days >= 365) {
+        const years = Math.floor(days / 365.25) || 1;
+        return `${years} ${years === 1 ? 'year' : 'years'} ago`;
+    }
+    if (days >= 30) {
+        const months = Math.floor(days / 30.44) || 1;
+        return `${months} ${months === 1 ? 'month' : 'months'} ago`;
+    }
+    return `${days.toLocaleString(LOCALE)} ${days === 1 ? 'day' : 'days'} ago`;
+}
+
+function renderTopPlacesSection(visitStats, revisits = {}) {
     const grid = document.getElementById('stat-top-places-grid');
     if (!grid) return;
     
     grid.innerHTML = '';
     
     // Get top places sorted by visit count (include places without names) - show only top 3
-    const allPlaces = Object.values(visitStats)
+    const allPlaces = Object.entries(visitStats)
+        .map(([placeId, place]) => ({ ...place, revisit: revisits[placeId] || null }))
         .sort((a, b) => b.count - a.count)
         .slice(0, 3);
     
@@ -941,13 +1292,14 @@ function renderTopPlacesSection(visitStats) {
                     <span class="text-gray-400 dark:text-gray-500 text-sm flex-shrink-0">#${index + 1}</span>
                     <span class="place-name ${!hasName ? 'text-gray-500 dark:text-gray-400 font-mono text-sm' : ''}" title="${displayTitle}">${displayName}</span>
                 </div>
-                ${place.country ? `<p class="text-xs text-gray-400 dark:text-gray-500 ml-6 mt-0.5">${place.country}</p>` : ''}
+                ${place.country && !isStatHidden('countries') ? `<p class="text-xs text-gray-400 dark:text-gray-500 ml-6 mt-0.5">${formatCountryWithFlag(place.country)}</p>` : ''}
+                ${place.revisit ? `<p class="text-xs text-gray-400 dark:text-gray-500 ml-6 mt-0.5">Last time here: ${formatTimeSince(place.revisit.daysSince)}</p>` : ''}
                 ${osmUrl ? `<a href="${osmUrl}" target="_blank" rel="noopener noreferrer" class="text-xs text-blue-600 dark:text-blue-400 ml-6 mt-1 inline-flex items-center gap-1 hover:underline">
                     <span>Open location</span>
                 </a>` : ''}
             </div>
             <div class="text-right flex-shrink-0">
-                <div class="place-count">${place.count}</div>
+                <div class="place-count">${place.count.toLocaleString(LOCALE)}</div>
                 <div class="place-count-label">visits</div>
             </div>
         `;
@@ -955,6 +1307,67 @@ function renderTopPlacesSection(visitStats) {
     });
 }
 
+function renderCountryHistory(countryHistory) {
+    const grid = document.getElementById('stat-countries-grid');
+    if (!grid) return;
+
+    grid.innerHTML = '';
+
+    // Countries sorted by total days spent - show top 6
+    const countries = Object.entries(countryHistory)
+        .sort(([, a], [, b]) => b.totalDays - a.totalDays)
+        .slice(0, 6);
+
+    if (countries.length === 0) {
+        grid.innerHTML = '<p class="col-span-full text-gray-500 dark:text-gray-400">No countries found</p>';
+        return;
+    }
+
+    const formatDate = (iso) => formatDisplayDate(iso, { month: 'short', year: 'numeric' });
+
+    countries.forEach(([country, history]) => {
+        const first = history.periods[0];
+        const last = history.periods[history.periods.length - 1];
+        const range = first === last ? formatDate(first.start) : `${formatDate(first.start)} – ${formatDate(last.start)}`;
+
+        const card = document.createElement('div');
+        card.className = 'place-stat-card';
+        card.innerHTML = `
+            <div class="flex-1 min-w-0">
+                <span class="place-name" title="${country}">${formatCountryWithFlag(country)}</span>
+                <p class="text-xs text-gray-400 dark:text-gray-500 mt-0.5">${history.trips.toLocaleString(LOCALE)} ${history.trips === 1 ? 'trip' : 'trips'} • ${range}</p>
+            </div>
+            <div class="text-right flex-shrink-0">
+                <div class="place-count">${history.totalDays.toLocaleString(LOCALE)}</div>
+                <div class="place-count-label">${history.totalDays === 1 ? 'day' : 'days'}</div>
+            </div>
+        `;
+        grid.appendChild(card);
+    });
+}
+
+// Helper to format a timestamp as the day it was recorded (its own UTC offset), in the configured locale
+function formatDisplayDate(isoString, options) {
+    return timelineUtils.formatLocalDate(isoString, LOCALE, options);
+}
+
+// Helper to format a number with a fixed number of decimals in the configured locale (e.g. 'de-DE' -> "12,5")
+function formatDecimal(value, digits) {
+    return value.toLocaleString(LOCALE, { minimumFractionDigits: digits, maximumFractionDigits: digits });
+}
+
+// Helper to format a lat/lng pair; comma-decimal locales separate the pair with a semicolon
+function formatCoordinatePair(lat, lng) {
+    const separator = formatDecimal(0.5, 1).includes(',') ? '; ' : ', ';
+    return `${formatDecimal(lat, 4)}${separator}${formatDecimal(lng, 4)}`;
+}
+
+// Helper to prefix a country name with its flag emoji when known
+function formatCountryWithFlag(country) {
+    const meta = timelineUtils.getCountryMetadata(country);
+    return meta && meta.flag ? `${meta.flag} ${country}` : country;
+}
+
 // Helper to format lat/lng string for display
 function formatLatLng(latLngStr) {
     if (!latLngStr) return 'Unknown Location';
@@ -964,7 +1377,7 @@ function formatLatLng(latLngStr) {
     const lat = parseFloat(parts[0].trim());
     const lng = parseFloat(parts[1].trim());
     if (isNaN(lat) || isNaN(lng)) return latLngStr;
-    return `${lat.toFixed(4)}, ${lng.toFixed(4)}`;
+    return formatCoordinatePair(lat, lng);
 }
 
 // Helper to build an OpenStreetMap URL for a given lat/lng string
@@ -988,7 +1401,7 @@ function renderStatistics(stats) {
     // Distance story section
     const distanceEl = document.getElementById('stat-distance');
     if (distanceEl) {
-        distanceEl.textContent = `${distanceKm.toLocaleString()} km`;
+        distanceEl.textContent = `${distanceKm.toLocaleString(LOCALE)} km`;
     }
     
     const distanceSubtitle = document.getElementById('stat-distance-subtitle');
@@ -1007,18 +1420,27 @@ function renderStatistics(stats) {
     // Places & Countries story section
     const placesCount = document.getElementById('stat-places-count');
     if (placesCount) {
-        placesCount.textContent = uniquePlacesCount.toLocaleString();
+        placesCount.textContent = uniquePlacesCount.toLocaleString(LOCALE);
     }
     
     const countriesCountEl = document.getElementById('stat-countries-count');
     if (countriesCountEl) {
-        countriesCountEl.textContent = countriesCount.toLocaleString();
+        countriesCountEl.textContent = countriesCount.toLocaleString(LOCALE);
+    }
+
+    const worldPercentEl = document.getElementById('stat-world-percent');
+    if (worldPercentEl) {
+        const coverage = timelineUtils.calculateWorldCoverage(stats.countries);
+        worldPercentEl.textContent = countriesCount > 0
+            ? `You've seen ${(coverage.worldPercent / 100).toLocaleString(LOCALE, { style: 'percent', minimumFractionDigits: 1, maximumFractionDigits: 1 })} of the world`
+            : '';
+        worldPercentEl.title = coverage.methodology;
     }
     
     // Visits story section
     const visitsEl = document.getElementById('stat-visits');
     if (visitsEl) {
-        visitsEl.textContent = stats.totalVisits.toLocaleString();
+        visitsEl.textContent = stats.totalVisits.toLocaleString(LOCALE);
     }
     
     // Also keep backward compatibility with hidden grid (for any other code referencing it)
@@ -1028,6 +1450,19 @@ function renderStatistics(stats) {
     }
 }
 
+function renderRepeatVisits(repeatVisits) {
+    const el = document.getElementById('stat-repeat-visits');
+    if (!el) return;
+    // All-time view has nothing to compare against
+    if (!repeatVisits || (repeatVisits.newCountries.length === 0 && repeatVisits.returningCountries.length === 0)) {
+        el.textContent = '';
+        return;
+    }
+    const newCount = repeatVisits.newCountries.length;
+    const returningCount = repeatVisits.returningCountries.length;
+    el.textContent = `${newCount.toLocaleString(LOCALE)} new ${newCount === 1 ? 'country' : 'countries'}, ${returningCount.toLocaleString(LOCALE)} you returned to`;
+}
+
 function createStatCard(title, value, iconName) {
     const div = document.createElement('div');
     // Glass styling for stat cards
@@ -1337,8 +1772,8 @@ function createTransportSceneCard(type, distanceKm, durationHours) {
 
     return createAnimatedStatScene({
         title: label,
-        value: `${distanceKm.toLocaleString()} km`,
-        subtitle: `${durationHours} hrs`,
+        value: `${distanceKm.toLocaleString(LOCALE)} km`,
+        subtitle: `${durationHours.toLocaleString(LOCALE)} hrs`,
         sceneType: sceneType,
         data: { distanceKm, durationHours }
     });
@@ -1503,7 +1938,7 @@ function getTripsAroundEarth(stats) {
     if (!stats || stats.totalDistanceMeters <= 0) return { value: 0, display: '0' };
     const distanceKm = stats.totalDistanceMeters / 1000;
     const value = distanceKm / EARTH_CIRCUMFERENCE_KM;
-    const display = value.toFixed(1);
+    const display = formatDecimal(value, 1);
     return { value, display };
 }
 
@@ -1513,41 +1948,17 @@ function buildShareDetails(mode, useGlobeBackground = false) {
     const advanced = useOverall ? lastAllTimeAdvancedStats : lastAdvancedStats;
     if (!stats) return null;
 
-    const distanceKm = Math.round(stats.totalDistanceMeters / 1000).toLocaleString();
-    const countries = stats.countries.size.toLocaleString();
-    const uniquePlaces = Object.keys(stats.visits || {}).length.toLocaleString();
-    const subtitle = useOverall ? 'Lifetime' : (selectedYear ? String(selectedYear) : 'All Years');
-
-    const statRows = [
-        { label: 'Distance', value: `${distanceKm} km` },
-        { label: 'Countries', value: countries },
-        { label: 'Unique places', value: uniquePlaces }
-    ];
+    const subtitle = useOverall ? 'Lifetime' : (getSelectedPeriodLabel() || 'All Years');
+    const statRows = timelineUtils.buildShareStatRows(stats, advanced, { locale: LOCALE, hiddenStats: HIDDEN_STATS });
 
     let tripsAroundEarth = null;
     // Latitude line on share image (globe only): always equator (0°) so it appears in the middle of the globe
     let shareParallelLatitude = null;
-    if (stats.totalDistanceMeters > 0) {
+    if (stats.totalDistanceMeters > 0 && !isStatHidden('distance')) {
         tripsAroundEarth = getTripsAroundEarth(stats);
         if (useGlobeBackground) shareParallelLatitude = 0;
     }
 
-    if (advanced && advanced.records) {
-        const driveKm = (advanced.records.longestDrive / 1000).toFixed(1);
-        const walkKm = (advanced.records.longestWalk / 1000).toFixed(1);
-        if (advanced.records.longestDrive > 0) statRows.push({ label: 'Longest drive', value: `${driveKm} km` });
-        if (advanced.records.longestWalk > 0) statRows.push({ label: 'Longest walk', value: `${walkKm} km` });
-    }
-
-    if (advanced && advanced.eco) {
-        const distanceByType = advanced.eco.distanceByType || {};
-        const nonVehicleKm = (distanceByType.WALKING || 0) + (distanceByType.RUNNING || 0) + (distanceByType.CYCLING || 0);
-        const savedKg = Math.round((nonVehicleKm * 150) / 1000);
-        if (savedKg > 0) {
-            statRows.push({ label: 'CO₂ reduced', value: `${savedKg.toLocaleString()} kg` });
-        }
-    }
-
     return { title: 'My Travel Recap', subtitle, statRows, tripsAroundEarth: tripsAroundEarth ? tripsAroundEarth.value : null, shareParallelLatitude };
 }
 
@@ -1645,7 +2056,7 @@ function drawShareOverlay(canvas, details, _pixelRatio, isDark, globeCenter) {
     });
 
     // "X.X Trips Around Earth" right-aligned: globe = just above equator; map = middle right
-    const tripsAroundEarthValue = details.tripsAroundEarth != null ? details.tripsAroundEarth.toFixed(1) : null;
+    const tripsAroundEarthValue = details.tripsAroundEarth != null ? formatDecimal(details.tripsAroundEarth, 1) : null;
     if (tripsAroundEarthValue != null) {
         const tripsText = `${tripsAroundEarthValue} Trips Around Earth`;
         const tripsFontSize = Math.round(28 * scale);
@@ -1910,23 +2321,30 @@ function renderTravelSummary(stats) {
     // Display as "X.X" trips (same stat as share card "Trips Around Earth")
     worldTripsEl.textContent = tripsAroundWorld;
     if (worldTripsLabel) {
-        const tripWord = parseFloat(tripsAroundWorld) === 1 ? 'Trip' : 'Trips';
+        const tripWord = Math.round(tripsValue * 10) / 10 === 1 ? 'Trip' : 'Trips';
         worldTripsLabel.textContent = `${tripWord} Around Earth`;
     }
 
     const countryCount = stats.countries.size;
     const uniquePlacesCount = Object.keys(stats.visits || {}).length;
+    const explored = [];
+    if (!isStatHidden('countries')) explored.push(`<strong>${countryCount.toLocaleString(LOCALE)} countries</strong>`);
+    if (!isStatHidden('places')) explored.push(`<strong>${uniquePlacesCount.toLocaleString(LOCALE)} unique places</strong>`);
+    const showDistance = !isStatHidden('distance');
 
-    if (countryCount > 0 || uniquePlacesCount > 0) {
+    if ((countryCount > 0 || uniquePlacesCount > 0) && explored.length > 0) {
         description.innerHTML = `
-            You've explored <strong>${countryCount} countries</strong> and <strong>${uniquePlacesCount} unique places</strong> this period.<br>
-            That's <strong>${tripsAroundWorld} trips</strong> around the Earth, over <strong>${Math.round(distanceKm).toLocaleString()} km</strong>.
-        `;
-    } else {
+            You've explored ${explored.join(' and ')} this period.`
+            + (showDistance ? `<br>
+            That's <strong>${tripsAroundWorld} trips</strong> around the Earth, over <strong>${Math.round(distanceKm).toLocaleString(LOCALE)} km</strong>.
+        ` : '');
+    } else if (showDistance) {
         description.innerHTML = `
-            You've travelled about <strong>${Math.round(distanceKm).toLocaleString()} km</strong> this period,<br>
+            You've travelled about <strong>${Math.round(distanceKm).toLocaleString(LOCALE)} km</strong> this period,<br>
             which is <strong>${tripsAroundWorld} trips</strong> around the Earth.
         `;
+    } else {
+        description.innerHTML = '';
     }
 }
 
@@ -1980,7 +2398,7 @@ function renderVisitTrends(visitStats) {
                 <p class="font-semibold text-gray-900 truncate w-48" title="${place.name}">${place.name}</p>
             </div>
             <div class="flex flex-col items-end">
-                <span class="text-xl font-bold text-blue-600 bg-blue-50 px-2 py-0.5 rounded-md min-w-[30px] text-center">${place.count}</span>
+                <span class="text-xl font-bold text-blue-600 bg-blue-50 px-2 py-0.5 rounded-md min-w-[30px] text-center">${place.count.toLocaleString(LOCALE)}</span>
             </div>
         `;
         grid.appendChild(card);
@@ -2023,12 +2441,12 @@ function renderAllTimeStats(stats) {
     container.innerHTML = '';
 
     const statsData = [
-        { label: 'Total Distance', value: Math.round(stats.totalDistanceMeters / 1000).toLocaleString() + ' km' },
-        { label: 'Total Visits', value: stats.totalVisits.toLocaleString() },
-        { label: 'Countries', value: stats.countries.size.toLocaleString() }
+        { key: 'distance', label: 'Total Distance', value: Math.round(stats.totalDistanceMeters / 1000).toLocaleString(LOCALE) + ' km' },
+        { key: 'visits', label: 'Total Visits', value: stats.totalVisits.toLocaleString(LOCALE) },
+        { key: 'countries', label: 'Countries', value: stats.countries.size.toLocaleString(LOCALE) }
     ];
 
-    statsData.forEach(stat => {
+    statsData.filter(stat => !isStatHidden(stat.key)).forEach(stat => {
         const div = document.createElement('div');
         div.innerHTML = `
             <p class="text-4xl md:text-5xl font-black text-white mb-2">${stat.value}</p>
@@ -2037,12 +2455,48 @@ function renderAllTimeStats(stats) {
         container.appendChild(div);
     });
 
-    const distanceEl = document.getElementById('all-time-distance');
-    const visitsEl = document.getElementById('all-time-visits');
-    const countriesEl = document.getElementById('all-time-countries');
-    if (distanceEl) distanceEl.textContent = statsData[0].value;
-    if (visitsEl) visitsEl.textContent = statsData[1].value;
-    if (countriesEl) countriesEl.textContent = statsData[2].value;
+    // Foreground text rows: value span plus its label
+    statsData.forEach(stat => {
+        const el = document.getElementById(`all-time-${stat.key}`);
+        if (!el) return;
+        el.textContent = isStatHidden(stat.key) ? '' : stat.value;
+        if (el.parentElement) el.parentElement.classList.toggle('hidden', isStatHidden(stat.key));
+    });
+}
+
+function renderLifetimeMilestones(lifetime) {
+    const milestonesEl = document.getElementById('all-time-milestones');
+    if (milestonesEl) {
+        const formatDate = (iso) => formatDisplayDate(iso, { month: 'short', year: 'numeric' });
+        const describe = (m) => {
+            if (m.type === 'countries') return m.count === 1 ? `First country: ${m.country}` : `${m.count.toLocaleString(LOCALE)}th country: ${m.country}`;
+            if (m.type === 'places') return `${m.count.toLocaleString(LOCALE)} unique places`;
+            if (m.type === 'trips') return `First trip abroad: ${m.country}`;
+            return `${m.count.toLocaleString(LOCALE)} km travelled`;
+        };
+        // Most recent milestones first (milestone types match HIDDEN_STATS keys; a trip names its country)
+        const isMilestoneHidden = (m) => isStatHidden(m.type === 'trips' ? 'countries' : m.type);
+        milestonesEl.innerHTML = lifetime.milestones.filter(m => !isMilestoneHidden(m)).slice(-4).reverse().map(m => `
+            <div class="flex items-baseline gap-2">
+                <span class="font-semibold text-gray-900 dark:text-white">${describe(m)}</span>
+                <span class="text-xs text-gray-500 dark:text-gray-400">${formatDate(m.date)}</span>
+            </div>
+        `).join('');
+    }
+
+    const decadesEl = document.getElementById('all-time-decades');
+    if (decadesEl) {
+        const showDistance = !isStatHidden('distance');
+        const showCountries = !isStatHidden('countries');
+        decadesEl.textContent = !showDistance && !showCountries ? '' : Object.entries(lifetime.decades)
+            .map(([decade, d]) => {
+                const parts = [];
+                if (showDistance) parts.push(`${Math.round(d.distanceMeters / 1000).toLocaleString(LOCALE)} km`);
+                if (showCountries) parts.push(`${d.countries.toLocaleString(LOCALE)} ${d.countries === 1 ? 'country' : 'countries'}`);
+                return `${decade}: ${parts.join(', ')}`;
+            })
+            .join(' · ');
+    }
 }
 
 // Helper: Ensure markers layer is cluster group or plain layer based on count
@@ -2077,18 +2531,13 @@ function renderMarkers(onDone) {
 
     let filteredLocations = allLocations;
 
-    // Apply year filter if selected
-    if (selectedYear) {
-        const yearInt = parseInt(selectedYear);
-        filteredLocations = allLocations.filter(loc => {
-            if (!loc.startTime) return false;
-            const visitYear = new Date(loc.startTime).getFullYear();
-            return visitYear === yearInt;
-        });
+    // Apply year or range filter if selected
+    if (selectedYear || selectedRange) {
+        filteredLocations = allLocations.filter(loc => isInSelectedPeriod(loc.startTime));
     }
 
     if (filteredLocations.length === 0) {
-        timelineUtils.Logger.warn('No locations found for selected year');
+        timelineUtils.Logger.warn('No locations found for selected period');
         markers.clearLayers();
         if (heatLayer && map.hasLayer(heatLayer)) map.removeLayer(heatLayer);
         if (typeof onDone === 'function') onDone();
@@ -2199,15 +2648,14 @@ function buildPopupContent(location) {
     const hasName = location.name && location.name.trim().length > 0;
     const title = hasName
         ? location.name
-        : (location.country || location.placeId || 'Location');
+        : ((!isStatHidden('countries') && location.country) || location.placeId || 'Location');
     content += `<strong>${title}</strong><br>`;
 
     if (location.startTime) {
-        const date = new Date(location.startTime);
-        content += `<span class="text-xs text-gray-500">${date.toLocaleDateString()}</span><br>`;
+        content += `<span class="text-xs text-gray-500">${formatDisplayDate(location.startTime)}</span><br>`;
     }
 
-    content += `<span class="text-xs text-gray-400">${location.lat.toFixed(4)}, ${location.lng.toFixed(4)}</span>`;
+    content += `<span class="text-xs text-gray-400">${formatCoordinatePair(location.lat, location.lng)}</span>`;
     content += '</div>';
     return content;
 }
@@ -1,5 +1,21 @@
 const timelineUtils = require('../timeline-utils');
 
+// Fixture helpers for timeline segments
+function visitSegment({ startTime, endTime, country, placeId, latLng, name }) {
+    const topCandidate = {};
+    if (placeId) topCandidate.placeId = placeId;
+    if (latLng) topCandidate.placeLocation = { latLng, name };
+    const segment = { startTime, endTime, visit: { topCandidate } };
+    if (country) segment.country = country;
+    return segment;
+}
+
+function activitySegment({ startTime, endTime, distanceMeters, type }) {
+    const activity = { distanceMeters };
+    if (type) activity.topCandidate = { type };
+    return { startTime, endTime, activity };
+}
+
 describe('Timeline Utilities', () => {
 
     describe('calculateStats', () => {
@@ -87,6 +103,445 @@ describe('Timeline Utilities', () => {
             const stats = timelineUtils.calculateAdvancedStats(segments);
             expect(stats.records.longestWalk).toBe(5000);
         });
+
+        test('should compute speeds per mode and flag impossible ones', () => {
+            const segments = [
+                {
+                    activity: { distanceMeters: 5000, topCandidate: { type: 'WALKING' } },
+                    startTime: '2024-01-01T10:00:00Z',
+                    endTime: '2024-01-01T11:00:00Z' // 5 km/h
+                },
+                {
+                    activity: { distanceMeters: 3000, topCandidate: { type: 'WALKING' } },
+                    startTime: '2024-01-02T10:00:00Z',
+                    endTime: '2024-01-02T10:30:00Z' // 6 km/h
+                },
+                {
+                    activity: { distanceMeters: 50000, topCandidate: { type: 'WALKING' } },
+                    startTime: '2024-01-03T10:00:00Z',
+                    endTime: '2024-01-03T11:00:00Z' // 50 km/h walking: glitch
+                },
+                {
+                    activity: { distanceMeters: 50, topCandidate: { type: 'CYCLING' } },
+                    startTime: '2024-01-04T10:00:00Z',
+                    endTime: '2024-01-04T10:00:05Z' // too short to measure
+                }
+            ];
+            const stats = timelineUtils.calculateAdvancedStats(segments);
+            expect(stats.speeds.WALKING.count).toBe(2);
+            expect(stats.speeds.WALKING.avgKmh).toBeCloseTo(8 / 1.5);
+            expect(stats.speeds.WALKING.maxKmh).toBeCloseTo(6);
+            expect(stats.speeds.CYCLING).toBeUndefined();
+            expect(stats.records.maxVelocity).toBeCloseTo(6);
+            expect(stats.speedGlitches).toHaveLength(1);
+            expect(stats.speedGlitches[0].startTime).toBe('2024-01-03T10:00:00Z');
+        });
+    });
+
+    describe('calculatePeriodBreakdown', () => {
+        test('should split distance and places by month and season', () => {
+            const segments = [
+                { startTime: '2024-07-10T12:00:00', activity: { distanceMeters: 3000 } },
+                { startTime: '2024-07-11T12:00:00', visit: { topCandidate: { placeId: 'A' } } },
+                { startTime: '2024-07-12T12:00:00', visit: { topCandidate: { placeId: 'A' } } },
+                { startTime: '2024-01-05T12:00:00', activity: { distanceMeters: 1000 } },
+                { visit: { topCandidate: { placeId: 'B' } } } // no timestamp: ignored
+            ];
+            const breakdown = timelineUtils.calculatePeriodBreakdown(segments);
+            expect(breakdown.months[6].distanceMeters).toBe(3000);
+            expect(breakdown.months[6].visits).toBe(2);
+            expect(breakdown.months[6].places).toBe(1);
+            expect(breakdown.months[0].distanceMeters).toBe(1000);
+            expect(breakdown.seasons.Summer.distanceMeters).toBe(3000);
+            expect(breakdown.seasons.Winter.distanceMeters).toBe(1000);
+            expect(breakdown.seasons.Spring.visits).toBe(0);
+        });
+
+        test('should attribute each country to the period it was first visited', () => {
+            const segments = [
+                { startTime: '2024-08-01T12:00:00', country: 'France', visit: { topCandidate: { placeId: 'P' } } },
+                { startTime: '2024-03-01T12:00:00', country: 'France', visit: { topCandidate: { placeId: 'Q' } } },
+                { startTime: '2024-08-02T12:00:00', country: 'Italy', visit: { topCandidate: { placeId: 'R' } } }
+            ];
+            const breakdown = timelineUtils.calculatePeriodBreakdown(segments);
+            expect(breakdown.months[2].newCountries).toEqual(['France']);
+            expect(breakdown.months[7].newCountries).toEqual(['Italy']);
+            expect(breakdown.seasons.Spring.newCountries).toEqual(['France']);
+        });
+
+        test('should not count countries first visited before the period as new', () => {
+            const earlier = { startTime: '2023-05-01T12:00:00+02:00', country: 'France', visit: { topCandidate: { placeId: 'P' } } };
+            const later = { startTime: '2024-05-01T12:00:00+02:00', country: 'France', visit: { topCandidate: { placeId: 'P' } } };
+            expect(timelineUtils.calculatePeriodBreakdown([later], [earlier, later]).months[4].newCountries).toEqual([]);
+            expect(timelineUtils.calculatePeriodBreakdown([earlier], [earlier, later]).months[4].newCountries).toEqual(['France']);
+        });
+
+        test('should use Southern Hemisphere seasons when most visits are south of the equator', () => {
+            const segments = [
+                { startTime: '2024-07-10T12:00:00', visit: { topCandidate: { placeId: 'S', placeLocation: { latLng: '-33.8688°, 151.2093°' } } } },
+                { startTime: '2024-01-10T12:00:00', visit: { topCandidate: { placeId: 'S', placeLocation: { latLng: '-33.8688°, 151.2093°' } } } }
+            ];
+            const breakdown = timelineUtils.calculatePeriodBreakdown(segments);
+            expect(breakdown.hemisphere).toBe('south');
+            expect(breakdown.seasons.Winter.visits).toBe(1);
+            expect(breakdown.seasons.Summer.visits).toBe(1);
+        });
+    });
+
+    describe('calculateExtremes', () => {
+        test('should find compass extremes and date range', () => {
+            const segments = [
+                visitSegment({ startTime: '2024-06-01T10:00:00Z', endTime: '2024-06-01T12:00:00Z', latLng: '64.1466°, -21.9426°', name: 'Reykjavik' }),
+                visitSegment({ startTime: '2024-02-01T10:00:00Z', endTime: '2024-02-01T12:00:00Z', latLng: '-33.8688°, 151.2093°', name: 'Sydney' }),
+                visitSegment({ startTime: '2024-11-20T10:00:00Z', endTime: '2024-11-20T18:00:00Z', latLng: 'geo:40.7128,-74.0060', name: 'New York' }),
+                activitySegment({ startTime: '2024-01-02T08:00:00Z', endTime: '2024-01-02T09:00:00Z', distanceMeters: 100 })
+            ];
+            const extremes = timelineUtils.calculateExtremes(segments);
+            expect(extremes.north.name).toBe('Reykjavik');
+            expect(extremes.south.name).toBe('Sydney');
+            expect(extremes.east.name).toBe('Sydney');
+            expect(extremes.west.name).toBe('New York');
+            expect(extremes.firstDate).toBe('2024-01-02T08:00:00Z');
+            expect(extremes.lastDate).toBe('2024-11-20T18:00:00Z');
+        });
+
+        test('should return nulls when there are no visits', () => {
+            const extremes = timelineUtils.calculateExtremes([]);
+            expect(extremes.north).toBeNull();
+            expect(extremes.firstDate).toBeNull();
+        });
+
+        test('should find the longest trip away from home by days and by distance', () => {
+            const segments = [
+                visitSegment({ startTime: '2024-01-01T08:00:00+01:00', endTime: '2024-01-20T08:00:00+01:00', country: 'Spain' }),
+                activitySegment({ startTime: '2024-01-20T10:00:00+01:00', endTime: '2024-01-20T10:00:00+01:00', distanceMeters: 1000000 }),
+                visitSegment({ startTime: '2024-01-20T14:00:00+01:00', endTime: '2024-01-21T10:00:00+01:00', country: 'France' }),
+                visitSegment({ startTime: '2024-01-22T10:00:00+01:00', endTime: '2024-01-22T18:00:00+01:00', country: 'Italy' }),
+                activitySegment({ startTime: '2024-01-23T10:00:00+01:00', endTime: '2024-01-23T10:00:00+01:00', distanceMeters: 1000000 }),
+                visitSegment({ startTime: '2024-01-23T14:00:00+01:00', endTime: '2024-03-01T08:00:00+01:00', country: 'Spain' }),
+                activitySegment({ startTime: '2024-03-01T10:00:00+01:00', endTime: '2024-03-01T20:00:00+01:00', distanceMeters: 8000000, type: 'FLYING' }),
+                visitSegment({ startTime: '2024-03-01T22:00:00+01:00', endTime: '2024-03-02T08:00:00+01:00', country: 'Japan' }),
+                visitSegment({ startTime: '2024-03-03T08:00:00+01:00', endTime: '2024-04-01T08:00:00+01:00', country: 'Spain' })
+            ];
+            const extremes = timelineUtils.calculateExtremes(segments);
+            expect(extremes.longestTripByDays.days).toBe(3);
+            expect(extremes.longestTripByDays.countries).toEqual(['France', 'Italy']);
+            expect(extremes.longestTripByDays.distanceMeters).toBe(2000000);
+            expect(extremes.longestTripByDistance.countries).toEqual(['Japan']);
+            expect(extremes.longestTripByDistance.distanceMeters).toBe(8000000);
+            expect(extremes.longestTripByDistance.days).toBe(2);
+            expect(extremes.longestTripByDistance.speeds.FLYING.avgKmh).toBeCloseTo(800);
+        });
+    });
+
+    describe('calculateRepeatVisits', () => {
+        test('should separate new and returning countries and places', () => {
+            const segments = [
+                visitSegment({ startTime: '2023-04-01T12:00:00', country: 'Portugal', placeId: 'lisbon' }),
+                visitSegment({ startTime: '2024-04-01T12:00:00', country: 'Portugal', placeId: 'lisbon' }),
+                visitSegment({ startTime: '2024-05-01T12:00:00', country: 'Portugal', placeId: 'porto' }),
+                visitSegment({ startTime: '2024-06-01T12:00:00', country: 'Japan', placeId: 'tokyo' }),
+                visitSegment({ startTime: '2025-01-01T12:00:00', country: 'Chile', placeId: 'santiago' })
+            ];
+            const repeat = timelineUtils.calculateRepeatVisits(segments, 2024);
+            expect(repeat.newCountries).toEqual(['Japan']);
+            expect(repeat.returningCountries).toEqual(['Portugal']);
+            expect(repeat.newPlaces).toBe(2);
+            expect(repeat.returningPlaces).toBe(1);
+        });
+    });
+
+    describe('calculateRevisits', () => {
+        test('should find the last visit before the year for returning places', () => {
+            const segments = [
+                visitSegment({ startTime: '2020-05-01T12:00:00Z', placeId: 'kyoto' }),
+                visitSegment({ startTime: '2021-05-01T12:00:00Z', placeId: 'kyoto' }),
+                visitSegment({ startTime: '2024-05-01T12:00:00Z', placeId: 'kyoto' }),
+                visitSegment({ startTime: '2024-06-01T12:00:00Z', placeId: 'kyoto' }),
+                visitSegment({ startTime: '2024-06-02T12:00:00Z', placeId: 'osaka' })
+            ];
+            const revisits = timelineUtils.calculateRevisits(segments, 2024);
+            expect(Object.keys(revisits)).toEqual(['kyoto']);
+            expect(revisits.kyoto.lastVisitedAt).toBe('2021-05-01T12:00:00Z');
+            expect(revisits.kyoto.visitedAt).toBe('2024-05-01T12:00:00Z');
+            expect(revisits.kyoto.daysSince).toBe(1096);
+        });
+    });
+
+    describe('calculateCountryHistory', () => {
+        test('should merge consecutive visits into stay periods per country', () => {
+            const segments = [
+                visitSegment({ startTime: '2023-04-01T10:00:00+09:00', endTime: '2023-04-01T20:00:00+09:00', country: 'Japan' }),
+                visitSegment({ startTime: '2023-04-03T10:00:00+09:00', endTime: '2023-04-05T09:00:00+09:00', country: 'Japan' }),
+                visitSegment({ startTime: '2023-04-06T10:00:00+09:00', endTime: '2023-04-06T12:00:00+09:00', country: 'South Korea' }),
+                visitSegment({ startTime: '2024-10-10T10:00:00+09:00', endTime: '2024-10-11T10:00:00+09:00', country: 'Japan' })
+            ];
+            const history = timelineUtils.calculateCountryHistory(segments);
+            expect(history.Japan.trips).toBe(2);
+            expect(history.Japan.periods[0]).toEqual({ start: '2023-04-01T10:00:00+09:00', end: '2023-04-05T09:00:00+09:00', days: 5 });
+            expect(history.Japan.totalDays).toBe(7);
+            expect(history['South Korea'].trips).toBe(1);
+            expect(history['South Korea'].totalDays).toBe(1);
+        });
+    });
+
+    describe('calculateLifetimeMilestones', () => {
+        test('should record milestones in chronological order and group by decade', () => {
+            const segments = [
+                { startTime: '2015-03-01T12:00:00', activity: { distanceMeters: 6000000 } },
+                { startTime: '2009-07-01T12:00:00', country: 'Spain', visit: { topCandidate: { placeId: 'madrid' } } },
+                { startTime: '2021-02-01T12:00:00', activity: { distanceMeters: 5000000 } },
+                { startTime: '2022-09-01T12:00:00', country: 'Peru', visit: { topCandidate: { placeId: 'lima' } } }
+            ];
+            const lifetime = timelineUtils.calculateLifetimeMilestones(segments);
+            expect(lifetime.milestones).toEqual([
+                { type: 'countries', count: 1, country: 'Spain', date: '2009-07-01T12:00:00' },
+                { type: 'distance', count: 10000, date: '2021-02-01T12:00:00' },
+                // Spain is home (first of the tied countries), so Peru is the first trip abroad
+                { type: 'trips', count: 1, country: 'Peru', date: '2022-09-01T12:00:00' }
+            ]);
+            expect(lifetime.decades['2000s'].countries).toBe(1);
+            expect(lifetime.decades['2010s'].distanceMeters).toBe(6000000);
+            expect(lifetime.decades['2020s'].visits).toBe(1);
+        });
+    });
+
+    describe('detectLocationGlitches', () => {
+        test('should flag a point that teleports away and back', () => {
+            const locations = [
+                { lat: 48.8566, lng: 2.3522, startTime: '2024-05-01T10:00:00Z' },   // Paris
+                { lat: 48.8606, lng: 2.3376, startTime: '2024-05-01T10:05:00Z' },   // Paris
+                { lat: 40.7128, lng: -74.0060, startTime: '2024-05-01T10:10:00Z' }, // New York, 5 minutes later
+                { lat: 48.8584, lng: 2.2945, startTime: '2024-05-01T10:15:00Z' }    // back in Paris
+            ];
+            const glitches = timelineUtils.detectLocationGlitches(locations);
+            expect(glitches).toHaveLength(1);
+            expect(glitches[0].index).toBe(2);
+            expect(glitches[0].kmh).toBeGreaterThan(1200);
+        });
+
+        test('should not flag a real flight', () => {
+            const locations = [
+                { lat: 48.8566, lng: 2.3522, startTime: '2024-05-01T10:00:00Z' },  // Paris
+                { lat: 40.7128, lng: -74.0060, startTime: '2024-05-01T18:00:00Z' } // New York, 8 hours later
+            ];
+            expect(timelineUtils.detectLocationGlitches(locations)).toHaveLength(0);
+        });
+    });
+
+    describe('findCoverageGaps', () => {
+        test('should report periods with no data longer than the threshold', () => {
+            const segments = [
+                { startTime: '2024-03-01T08:00:00Z', endTime: '2024-03-12T08:00:00Z', visit: {} },
+                { startTime: '2024-03-05T08:00:00Z', endTime: '2024-03-06T08:00:00Z', activity: {} }, // inside covered time
+                { startTime: '2024-03-19T08:00:00Z', endTime: '2024-03-20T08:00:00Z', visit: {} },
+                { startTime: '2024-03-21T08:00:00Z', endTime: '2024-03-21T09:00:00Z', visit: {} } // one-day gap: ignored
+            ];
+            const gaps = timelineUtils.findCoverageGaps(segments);
+            expect(gaps).toEqual([
+                { start: '2024-03-12T08:00:00Z', end: '2024-03-19T08:00:00Z', days: 7 }
+            ]);
+            expect(timelineUtils.findCoverageGaps(segments, 1)).toHaveLength(2);
+        });
+
+        test('should keep the recorded UTC offset in gap bounds', () => {
+            const segments = [
+                { startTime: '2024-03-01T20:00:00.000-08:00', endTime: '2024-03-01T22:00:00.000-08:00', visit: {} },
+                { startTime: '2024-03-05T21:00:00.000-08:00', endTime: '2024-03-05T23:00:00.000-08:00', visit: {} }
+            ];
+            const [gap] = timelineUtils.findCoverageGaps(segments);
+            expect(gap.start).toBe('2024-03-01T22:00:00.000-08:00');
+            expect(gap.end).toBe('2024-03-05T21:00:00.000-08:00');
+            expect(timelineUtils.formatLocalDate(gap.start, 'en-US', { month: 'short', day: 'numeric' })).toBe('Mar 1');
+        });
+    });
+
+    describe('calculateWorldCoverage', () => {
+        // Two 1°x1° squares on the equator: equal area
+        const square = (lng) => ({
+            type: 'Polygon',
+            coordinates: [[[lng, 0], [lng + 1, 0], [lng + 1, 1], [lng, 1], [lng, 0]]]
+        });
+
+        test('should compute country share against UN members', () => {
+            timelineUtils.setCountryGeoJSON(null);
+            const coverage = timelineUtils.calculateWorldCoverage(new Set(['France', 'Italy', 'French Polynesia', 'Antarctica']));
+            expect(coverage.countriesVisited).toBe(4);
+            expect(coverage.unMembersVisited).toBe(2);
+            expect(coverage.countryPercent).toBeCloseTo((2 / 193) * 100);
+            expect(coverage.areaPercent).toBeNull();
+            expect(coverage.worldPercent).toBeCloseTo(coverage.countryPercent);
+        });
+
+        test('should compute visited land area from boundaries', () => {
+            timelineUtils.setCountryGeoJSON({
+                features: [
+                    { properties: { name: 'A' }, geometry: square(0) },
+                    { properties: { name: 'B' }, geometry: square(10) }
+                ]
+            });
+            const coverage = timelineUtils.calculateWorldCoverage(['A']);
+            expect(coverage.areaPercent).toBeCloseTo(50);
+            expect(coverage.visitedAreaKm2).toBeGreaterThan(12000);
+            expect(coverage.visitedAreaKm2).toBeLessThan(12500);
+            expect(coverage.methodology).toContain('land area');
+            timelineUtils.setCountryGeoJSON(null);
+        });
+    });
+
+    describe('setCountryScheme', () => {
+        const segments = [
+            { country: 'Hong Kong S.A.R.', visit: { topCandidate: { placeId: 'HK' } } },
+            { country: 'Czech Republic', visit: { topCandidate: { placeId: 'PRG' } } }
+        ];
+
+        test('should count territories separately by default and apply renames', () => {
+            timelineUtils.setCountryScheme('boundaries');
+            const stats = timelineUtils.calculateStats(segments);
+            expect(Array.from(stats.countries)).toEqual(['Hong Kong S.A.R.', 'Czechia']);
+            expect(stats.countryScheme.name).toBe('boundaries');
+        });
+
+        test('should fold territories into UN members and honour custom aliases', () => {
+            timelineUtils.setCountryScheme('un', { 'Czechia': 'Czech Lands' });
+            const stats = timelineUtils.calculateStats(segments);
+            expect(Array.from(stats.countries)).toEqual(['China', 'Czech Lands']);
+            expect(stats.countryScheme.name).toBe('un');
+            timelineUtils.setCountryScheme('boundaries');
+        });
+
+        test('should count every boundary as one of the 193 UN members, or not at all', () => {
+            const fs = require('fs');
+            const path = require('path');
+            const geojson = JSON.parse(fs.readFileSync(path.join(__dirname, '../data/countries.geojson'), 'utf8'));
+            const names = geojson.features.map(f => f.properties.name);
+            const members = timelineUtils.UN_MEMBER_STATES;
+            expect(new Set(members).size).toBe(193);
+            members.forEach(member => expect(names).toContain(member));
+
+            timelineUtils.setCountryScheme('un');
+            const stats = timelineUtils.calculateStats(names.map(name => ({ country: name, visit: { topCandidate: { placeId: name } } })));
+            Array.from(stats.countries).forEach(country => expect(members).toContain(country));
+            expect(stats.countries.size).toBe(193);
+
+            const kosovo = timelineUtils.calculateStats([{ country: 'Kosovo', visit: { topCandidate: { placeId: 'PRN' } } }]);
+            expect(Array.from(kosovo.countries)).toEqual(['Republic of Serbia']);
+            const vatican = timelineUtils.calculateStats([{ country: 'Vatican', visit: { topCandidate: { placeId: 'VA' } } }]);
+            expect(vatican.countries.size).toBe(0);
+            timelineUtils.setCountryScheme('boundaries');
+        });
+    });
+
+    describe('getCountryMetadata', () => {
+        test('should return ISO codes and flag from boundary properties', () => {
+            timelineUtils.setCountryGeoJSON({
+                features: [
+                    { properties: { name: 'Japan', 'ISO3166-1-Alpha-2': 'JP', 'ISO3166-1-Alpha-3': 'JPN' }, geometry: null },
+                    { properties: { name: 'France', 'ISO3166-1-Alpha-2': '-99', 'ISO3166-1-Alpha-3': '-99' }, geometry: null }
+                ]
+            });
+            expect(timelineUtils.getCountryMetadata('Japan')).toEqual({ name: 'Japan', iso2: 'JP', iso3: 'JPN', flag: '🇯🇵' });
+            expect(timelineUtils.getCountryMetadata('France').iso2).toBe('FR');
+            expect(timelineUtils.getCountryMetadata('Atlantis')).toBeNull();
+            timelineUtils.setCountryGeoJSON(null);
+        });
+    });
+
+    describe('timestamp backfill', () => {
+        test('should derive missing timestamps from neighbouring segments', () => {
+            const data = {
+                semanticSegments: [
+                    { visit: {} },
+                    { startTime: '2024-02-01T10:00:00Z', endTime: '2024-02-01T11:00:00Z', visit: {} },
+                    { visit: {} },
+                    { startTime: '2024-02-01T12:00:00Z', visit: {} }
+                ]
+            };
+            const processed = timelineUtils.processTimelineData(data);
+            const [first, , third, fourth] = processed.allSegments;
+            expect(first.startTime).toBe('2024-02-01T10:00:00Z');
+            expect(first.timeDerived).toBe(true);
+            expect(third.startTime).toBe('2024-02-01T11:00:00Z');
+            expect(third.endTime).toBe('2024-02-01T11:00:00Z');
+            expect(fourth.endTime).toBe('2024-02-01T12:00:00Z');
+            expect(processed.allSegments[1].timeDerived).toBeUndefined();
+            expect(processed.timeBackfill).toEqual({ derived: 3, untimed: 0 });
+        });
+
+        test('should count segments with nothing to derive from', () => {
+            const processed = timelineUtils.processTimelineData({ semanticSegments: [{ visit: {} }, { activity: {} }] });
+            expect(processed.timeBackfill).toEqual({ derived: 0, untimed: 2 });
+            expect(processed.years).toEqual([]);
+        });
+
+        test('should leave recorded timestamps that are not ISO strings alone', () => {
+            const segment = { startTime: 1719835200000, endTime: '2024-07-01T13:00:00+02:00', visit: {} };
+            const processed = timelineUtils.processTimelineData({ semanticSegments: [segment] });
+            expect(processed.allSegments[0].startTime).toBe(1719835200000);
+            expect(processed.allSegments[0].timeDerived).toBeUndefined();
+            expect(processed.timeBackfill).toEqual({ derived: 0, untimed: 1 });
+        });
+
+        test('should only fill missing fields and start from the segment\'s own end', () => {
+            const data = {
+                semanticSegments: [
+                    { endTime: '2024-02-01T09:00:00+01:00', activity: { distanceMeters: '90000', topCandidate: { type: 'IN_PASSENGER_VEHICLE' } } },
+                    { startTime: '2024-02-01T10:00:00+01:00', endTime: '2024-02-01T11:00:00+01:00', activity: { distanceMeters: '20000', topCandidate: { type: 'IN_PASSENGER_VEHICLE' } } }
+                ]
+            };
+            const processed = timelineUtils.processTimelineData(data);
+            const [first] = processed.allSegments;
+            expect(first.startTime).toBe('2024-02-01T09:00:00+01:00');
+            expect(first.endTime).toBe('2024-02-01T09:00:00+01:00');
+            expect(first.timeDerived).toBe(true);
+            expect(processed.timeBackfill).toEqual({ derived: 1, untimed: 0 });
+
+            // Derived segments stay out of records, speeds and the first/last date
+            const advanced = timelineUtils.calculateAdvancedStats(processed.allSegments);
+            expect(advanced.records.longestDrive).toBe(20000);
+            expect(advanced.speeds['IN_PASSENGER_VEHICLE'].count).toBe(1);
+            expect(timelineUtils.calculateExtremes(processed.allSegments).firstDate).toBe('2024-02-01T10:00:00+01:00');
+        });
+    });
+
+    describe('getLocalDateParts', () => {
+        test('should use the recorded UTC offset, not the browser time zone', () => {
+            // 2025-01-01T09:30Z in UTC, but still New Year's Eve where it was recorded
+            expect(timelineUtils.getLocalDateParts('2024-12-31T23:30:00.000-10:00')).toEqual({ year: 2024, month: 11, day: 31 });
+            expect(timelineUtils.getLocalDateKey('2026-01-29T00:15:00+0530')).toBe('2026-01-29');
+        });
+
+        test('should return null for missing or invalid timestamps', () => {
+            expect(timelineUtils.getLocalDateParts(undefined)).toBeNull();
+            expect(timelineUtils.getLocalDateParts('not a date')).toBeNull();
+        });
+
+        test('should format the recorded day, not the browser day', () => {
+            expect(timelineUtils.formatLocalDate('2024-12-31T23:30:00.000-10:00', 'en-US')).toBe('12/31/2024');
+            expect(timelineUtils.formatLocalDate('2025-01-01T00:30:00+09:00', 'en-US', { month: 'short', day: 'numeric' })).toBe('Jan 1');
+            expect(timelineUtils.formatLocalDate(null, 'en-US')).toBe('');
+        });
+
+        test('should skip non-string timestamps in date-grouped stats', () => {
+            const segments = [
+                { startTime: 1719835200000, country: 'Peru', visit: { topCandidate: { placeId: 'X' } } },
+                { startTime: '2024-07-02T12:00:00-05:00', country: 'Peru', visit: { topCandidate: { placeId: 'X' } } }
+            ];
+            expect(timelineUtils.calculatePeriodBreakdown(segments).months[6].visits).toBe(1);
+            expect(timelineUtils.calculateLifetimeMilestones(segments).decades['2020s'].visits).toBe(1);
+            expect(timelineUtils.calculateRevisits(segments, 2024)).toEqual({});
+            expect(timelineUtils.calculateCountryHistory(segments).Peru.trips).toBe(1);
+        });
+    });
+
+    describe('isInLocalDateRange', () => {
+        test('should compare local calendar dates against inclusive bounds', () => {
+            // Recorded on 31 Aug locally, already 1 Sep in UTC
+            expect(timelineUtils.isInLocalDateRange('2024-08-31T20:00:00-07:00', '2023-09-01', '2024-08-31')).toBe(true);
+            expect(timelineUtils.isInLocalDateRange('2024-09-01T00:30:00+02:00', '2023-09-01', '2024-08-31')).toBe(false);
+            expect(timelineUtils.isInLocalDateRange('2023-09-01T00:00:00+02:00', '2023-09-01', null)).toBe(true);
+            expect(timelineUtils.isInLocalDateRange(undefined, '2023-09-01', '2024-08-31')).toBe(false);
+        });
     });
 
     describe('processTimelineData', () => {
@@ -157,6 +612,66 @@ describe('Timeline Utilities', () => {
             expect(stats.transport['IN_BUS']).toBeDefined();
             expect(stats.transport['IN_BUS'].distanceMeters).toBeCloseTo(8454.921875);
         });
+
+        test('should group years by local time and keep offsets on path points', () => {
+            const data = [
+                {
+                    startTime: '2024-12-31T23:00:00.000-10:00',
+                    endTime: '2025-01-01T01:00:00.000-10:00',
+                    timelinePath: [
+                        { point: 'geo:21.3069,-157.8583', durationMinutesOffsetFromStartTime: '90' }
+                    ]
+                }
+            ];
+            const processed = timelineUtils.processTimelineData(data);
+            expect(processed.years).toEqual([2024]);
+            expect(processed.allLocations[0].startTime).toBe('2025-01-01T00:30:00.000-10:00');
+        });
+    });
+
+    describe('share image rows', () => {
+        const stats = {
+            totalDistanceMeters: 123456,
+            countries: new Set(['France', 'Spain']),
+            visits: { a: 1, b: 2, c: 3 }
+        };
+        const advanced = {
+            records: { longestDrive: 45200, longestWalk: 8100 },
+            eco: { distanceByType: { WALKING: 20, CYCLING: 30 } }
+        };
+        const labels = rows => rows.map(row => row.label);
+
+        test('should build every row when nothing is hidden', () => {
+            const rows = timelineUtils.buildShareStatRows(stats, advanced, { locale: 'en-US' });
+            expect(labels(rows)).toEqual(['Distance', 'Countries', 'Unique places', 'Longest drive', 'Longest walk', 'CO₂ reduced']);
+            expect(rows[0].value).toBe('123 km');
+            expect(rows[1].value).toBe('2');
+        });
+
+        test('should format share values in the given locale', () => {
+            const rows = timelineUtils.buildShareStatRows(stats, advanced, { locale: 'de-DE' });
+            expect(rows.find(row => row.label === 'Longest drive').value).toBe('45,2 km');
+            expect(rows.find(row => row.label === 'Longest walk').value).toBe('8,1 km');
+        });
+
+        test('should leave out rows for hidden stats', () => {
+            const withoutCountries = timelineUtils.buildShareStatRows(stats, advanced, { hiddenStats: new Set(['countries']) });
+            expect(labels(withoutCountries)).not.toContain('Countries');
+            expect(labels(withoutCountries)).toContain('Distance');
+
+            const withoutDistance = timelineUtils.buildShareStatRows(stats, advanced, { hiddenStats: ['distance'] });
+            expect(labels(withoutDistance)).not.toContain('Distance');
+
+            const withoutRecords = timelineUtils.buildShareStatRows(stats, advanced, { hiddenStats: ['records', 'eco'] });
+            expect(labels(withoutRecords)).toEqual(['Distance', 'Countries', 'Unique places']);
+        });
+
+        test('should match share labels to their stat keys', () => {
+            expect(timelineUtils.isShareLabelHidden('Countries', ['countries'])).toBe(true);
+            expect(timelineUtils.isShareLabelHidden('Longest walk', new Set(['records']))).toBe(true);
+            expect(timelineUtils.isShareLabelHidden('Distance', ['countries'])).toBe(false);
+            expect(timelineUtils.isShareLabelHidden('Distance', undefined)).toBe(false);
+        });
     });
 
 });
@@ -85,6 +85,12 @@
         return { minX: minLng, minY: minLat, maxX: maxLng, maxY: maxLat };
     }
 
+    // Country name of a boundary feature (property names differ between GeoJSON sources)
+    function featureName(f) {
+        const props = f.properties || {};
+        return props.ADMIN || props.name || props.NAME || null;
+    }
+
     const rbushGlobal = typeof rbush !== 'undefined' ? rbush : (typeof self !== 'undefined' && self.rbush) ? self.rbush : null;
 
     class CountryLookup {
@@ -104,13 +110,44 @@
             }
         }
 
+        // Land area (km²) per country name, computed once from the boundaries
+        getAreas() {
+            if (!this.areas) {
+                this.areas = {};
+                for (const f of this.features) {
+                    const name = featureName(f);
+                    if (!name) continue;
+                    this.areas[name] = (this.areas[name] || 0) + geometryAreaKm2(f.geometry);
+                }
+            }
+            return this.areas;
+        }
+
+        // ISO codes per country name from the boundary properties
+        getCodes() {
+            if (!this.codes) {
+                this.codes = {};
+                for (const f of this.features) {
+                    const name = featureName(f);
+                    if (!name || this.codes[name]) continue;
+                    const props = f.properties || {};
+                    const iso2 = props['ISO3166-1-Alpha-2'] || props.ISO_A2 || null;
+                    const iso3 = props['ISO3166-1-Alpha-3'] || props.ISO_A3 || null;
+                    this.codes[name] = {
+                        iso2: /^[A-Z]{2}$/.test(iso2 || '') ? iso2 : null,
+                        iso3: /^[A-Z]{3}$/.test(iso3 || '') ? iso3 : null
+                    };
+                }
+            }
+            return this.codes;
+        }
+
         getCountry(lat, lng) {
             if (this.index) {
                 const candidates = this.index.search({ minX: lng, minY: lat, maxX: lng, maxY: lat });
                 for (const item of candidates) {
                     const f = item.feature;
-                    const props = f.properties || {};
-                    const name = props.ADMIN || props.name || props.NAME || null;
+                    const name = featureName(f);
                     const geom = f.geometry;
                     if (!geom || !geom.type || !geom.coordinates) continue;
                     if (geom.type === 'Polygon') {
@@ -122,8 +159,7 @@
                 return null;
             }
             for (const f of this.features) {
-                const props = f.properties || {};
-                const name = props.ADMIN || props.name || props.NAME || null;
+                const name = featureName(f);
                 const geom = f.geometry;
                 if (!geom || !geom.type || !geom.coordinates) continue;
                 if (geom.type === 'Polygon') {
@@ -136,6 +172,200 @@
         }
     }
 
+    const EARTH_RADIUS_KM = 6371.0088;
+
+    // Spherical area (km²) of a GeoJSON ring of [lng, lat] pairs
+    function ringAreaKm2(ring) {
+        const toRad = Math.PI / 180;
+        let total = 0;
+        for (let i = 0, j = ring.length - 1; i < ring.length; j = i++) {
+            const [lng1, lat1] = ring[j];
+            const [lng2, lat2] = ring[i];
+            total += (lng2 - lng1) * toRad * (2 + Math.sin(lat1 * toRad) + Math.sin(lat2 * toRad));
+        }
+        return Math.abs(total * EARTH_RADIUS_KM * EARTH_RADIUS_KM / 2);
+    }
+
+    // Polygon area = outer ring minus holes
+    function polygonAreaKm2(rings) {
+        if (!rings.length) return 0;
+        return Math.max(0, rings.slice(1).reduce((area, hole) => area - ringAreaKm2(hole), ringAreaKm2(rings[0])));
+    }
+
+    function geometryAreaKm2(geom) {
+        if (!geom || !geom.coordinates) return 0;
+        if (geom.type === 'Polygon') return polygonAreaKm2(geom.coordinates);
+        if (geom.type === 'MultiPolygon') return geom.coordinates.reduce((sum, poly) => sum + polygonAreaKm2(poly), 0);
+        return 0;
+    }
+
+    // Natural Earth leaves a few ISO codes as "-99" or non-standard; fill in the well-known ones
+    const ISO_CODE_OVERRIDES = {
+        'France': { iso2: 'FR', iso3: 'FRA' },
+        'Norway': { iso2: 'NO', iso3: 'NOR' },
+        'Kosovo': { iso2: 'XK', iso3: 'XKX' },
+        'Taiwan': { iso2: 'TW', iso3: 'TWN' }
+    };
+
+    // Regional indicator symbols for a two-letter code, e.g. "JP" -> 🇯🇵
+    function flagEmoji(iso2) {
+        if (!iso2 || !/^[A-Z]{2}$/.test(iso2)) return null;
+        return String.fromCodePoint(...[...iso2].map(c => 0x1F1E6 + c.charCodeAt(0) - 65));
+    }
+
+    // ===== COUNTRY COUNTING SCHEMES (DISPUTED / RENAMED TERRITORIES) =====
+
+    // Older names that may appear in stamped exports -> current boundary names (applied in every scheme)
+    const RENAMED_COUNTRIES = {
+        'Czech Republic': 'Czechia',
+        'Swaziland': 'eSwatini',
+        'Macedonia': 'North Macedonia',
+        'Republic of Macedonia': 'North Macedonia',
+        'Burma': 'Myanmar',
+        "Côte d'Ivoire": 'Ivory Coast',
+        'Timor-Leste': 'East Timor'
+    };
+
+    // UN member states, as named in the country boundaries file
+    const UN_MEMBER_STATES = [
+        'Afghanistan', 'Albania', 'Algeria', 'Andorra', 'Angola', 'Antigua and Barbuda', 'Argentina',
+        'Armenia', 'Australia', 'Austria', 'Azerbaijan', 'Bahrain', 'Bangladesh', 'Barbados', 'Belarus',
+        'Belgium', 'Belize', 'Benin', 'Bhutan', 'Bolivia', 'Bosnia and Herzegovina', 'Botswana', 'Brazil',
+        'Brunei', 'Bulgaria', 'Burkina Faso', 'Burundi', 'Cabo Verde', 'Cambodia', 'Cameroon', 'Canada',
+        'Central African Republic', 'Chad', 'Chile', 'China', 'Colombia', 'Comoros', 'Costa Rica',
+        'Croatia', 'Cuba', 'Cyprus', 'Czechia', 'Democratic Republic of the Congo', 'Denmark', 'Djibouti',
+        'Dominica', 'Dominican Republic', 'East Timor', 'Ecuador', 'Egypt', 'El Salvador',
+        'Equatorial Guinea', 'Eritrea', 'Estonia', 'eSwatini', 'Ethiopia',
+        'Federated States of Micronesia', 'Fiji', 'Finland', 'France', 'Gabon', 'Gambia', 'Georgia',
+        'Germany', 'Ghana', 'Greece', 'Grenada', 'Guatemala', 'Guinea', 'Guinea-Bissau', 'Guyana', 'Haiti',
+        'Honduras', 'Hungary', 'Iceland', 'India', 'Indonesia', 'Iran', 'Iraq', 'Ireland', 'Israel',
+        'Italy', 'Ivory Coast', 'Jamaica', 'Japan', 'Jordan', 'Kazakhstan', 'Kenya', 'Kiribati', 'Kuwait',
+        'Kyrgyzstan', 'Laos', 'Latvia', 'Lebanon', 'Lesotho', 'Liberia', 'Libya', 'Liechtenstein',
+        'Lithuania', 'Luxembourg', 'Madagascar', 'Malawi', 'Malaysia', 'Maldives', 'Mali', 'Malta',
+        'Marshall Islands', 'Mauritania', 'Mauritius', 'Mexico', 'Moldova', 'Monaco', 'Mongolia',
+        'Montenegro', 'Morocco', 'Mozambique', 'Myanmar', 'Namibia', 'Nauru', 'Nepal', 'Netherlands',
+        'New Zealand', 'Nicaragua', 'Niger', 'Nigeria', 'North Korea', 'North Macedonia', 'Norway', 'Oman',
+        'Pakistan', 'Palau', 'Panama', 'Papua New Guinea', 'Paraguay', 'Peru', 'Philippines', 'Poland',
+        'Portugal', 'Qatar', 'Republic of Serbia', 'Republic of the Congo', 'Romania', 'Russia', 'Rwanda',
+        'Saint Kitts and Nevis', 'Saint Lucia', 'Saint Vincent and the Grenadines', 'Samoa', 'San Marino',
+        'Saudi Arabia', 'Senegal', 'Seychelles', 'Sierra Leone', 'Singapore', 'Slovakia', 'Slovenia',
+        'Solomon Islands', 'Somalia', 'South Africa', 'South Korea', 'South Sudan', 'Spain', 'Sri Lanka',
+        'Sudan', 'Suriname', 'Sweden', 'Switzerland', 'Syria', 'São Tomé and Principe', 'Tajikistan',
+        'Thailand', 'The Bahamas', 'Togo', 'Tonga', 'Trinidad and Tobago', 'Tunisia', 'Turkey',
+        'Turkmenistan', 'Tuvalu', 'Uganda', 'Ukraine', 'United Arab Emirates', 'United Kingdom',
+        'United Republic of Tanzania', 'United States of America', 'Uruguay', 'Uzbekistan', 'Vanuatu',
+        'Venezuela', 'Vietnam', 'Yemen', 'Zambia', 'Zimbabwe'
+    ];
+
+    const COUNTRY_SCHEMES = {
+        boundaries: {
+            description: 'Every territory in the country boundaries file counts separately.',
+            mapping: {}
+        },
+        un: {
+            description: 'Only UN member states count: territories are folded into the member state that administers them, and observer states (Vatican, Palestine) or unadministered land (Antarctica, Western Sahara) are not counted.',
+            mapping: {
+                // Special administrative regions, and territories the UN counts as part of a member
+                'Hong Kong S.A.R.': 'China',
+                'Macao S.A.R': 'China',
+                'Taiwan': 'China',
+                'Scarborough Reef': 'China',
+                'Kosovo': 'Republic of Serbia',
+                'Northern Cyprus': 'Cyprus',
+                'Cyprus No Mans Area': 'Cyprus',
+                'Somaliland': 'Somalia',
+                'Siachen Glacier': 'India',
+                'Baykonur Cosmodrome': 'Kazakhstan',
+                'US Naval Base Guantanamo Bay': 'Cuba',
+                'Bajo Nuevo Bank (Petrel Is.)': 'Colombia',
+                'Serranilla Bank': 'Colombia',
+                'Brazilian Island': 'Brazil',
+                // Dependencies and overseas territories
+                'Aland': 'Finland',
+                'Faroe Islands': 'Denmark',
+                'Greenland': 'Denmark',
+                'Aruba': 'Netherlands',
+                'Curaçao': 'Netherlands',
+                'Sint Maarten': 'Netherlands',
+                'Cook Islands': 'New Zealand',
+                'Niue': 'New Zealand',
+                'Akrotiri Sovereign Base Area': 'United Kingdom',
+                'Dhekelia Sovereign Base Area': 'United Kingdom',
+                'Anguilla': 'United Kingdom',
+                'Bermuda': 'United Kingdom',
+                'British Indian Ocean Territory': 'United Kingdom',
+                'British Virgin Islands': 'United Kingdom',
+                'Cayman Islands': 'United Kingdom',
+                'Falkland Islands': 'United Kingdom',
+                'Gibraltar': 'United Kingdom',
+                'Guernsey': 'United Kingdom',
+                'Isle of Man': 'United Kingdom',
+                'Jersey': 'United Kingdom',
+                'Montserrat': 'United Kingdom',
+                'Pitcairn Islands': 'United Kingdom',
+                'Saint Helena': 'United Kingdom',
+                'South Georgia and the Islands': 'United Kingdom',
+                'Turks and Caicos Islands': 'United Kingdom',
+                'Clipperton Island': 'France',
+                'French Polynesia': 'France',
+                'French Southern and Antarctic Lands': 'France',
+                'New Caledonia': 'France',
+                'Saint Barthelemy': 'France',
+                'Saint Martin': 'France',
+                'Saint Pierre and Miquelon': 'France',
+                'Wallis and Futuna': 'France',
+                'American Samoa': 'United States of America',
+                'Guam': 'United States of America',
+                'Northern Mariana Islands': 'United States of America',
+                'Puerto Rico': 'United States of America',
+                'United States Minor Outlying Islands': 'United States of America',
+                'United States Virgin Islands': 'United States of America',
+                'Ashmore and Cartier Islands': 'Australia',
+                'Coral Sea Islands': 'Australia',
+                'Heard Island and McDonald Islands': 'Australia',
+                'Indian Ocean Territories': 'Australia',
+                'Norfolk Island': 'Australia',
+                // Not counted: observer states, and land no single member state administers
+                'Vatican': null,
+                'Palestine': null,
+                'Western Sahara': null,
+                'Antarctica': null,
+                'Bir Tawil': null,
+                'Spratly Islands': null,
+                'Southern Patagonian Ice Field': null
+            }
+        }
+    };
+
+    let countryScheme = 'boundaries';
+    let countryMapping = Object.assign({}, RENAMED_COUNTRIES);
+
+    /**
+     * Choose how countries are counted ('boundaries' or 'un'), with optional custom aliases
+     * ({ "From name": "Counted as" }) layered on top. Unknown schemes fall back to 'boundaries'.
+     */
+    function setCountryScheme(scheme, aliases) {
+        if (scheme && !COUNTRY_SCHEMES[scheme]) {
+            Logger.warn(`Unknown country scheme "${scheme}", using "boundaries"`);
+        }
+        countryScheme = COUNTRY_SCHEMES[scheme] ? scheme : 'boundaries';
+        countryMapping = Object.assign({}, RENAMED_COUNTRIES, COUNTRY_SCHEMES[countryScheme].mapping, aliases || {});
+    }
+
+    function getCountryScheme() {
+        return { name: countryScheme, description: COUNTRY_SCHEMES[countryScheme].description };
+    }
+
+    // Follow the mapping chain (rename -> scheme -> alias), guarding against cycles.
+    // Returns null for names the scheme does not count.
+    function normalizeCountry(name) {
+        let current = name;
+        for (let hops = 0; current && countryMapping[current] !== undefined && countryMapping[current] !== current && hops < 3; hops++) {
+            current = countryMapping[current];
+        }
+        return current || null;
+    }
+
     let countryLookupInstance = null;
 
     /**
@@ -156,7 +386,30 @@
 
     function getCountryFromLatLng(lat, lng) {
         if (!countryLookupInstance) return null;
-        return countryLookupInstance.getCountry(lat, lng);
+        return normalizeCountry(countryLookupInstance.getCountry(lat, lng));
+    }
+
+    /**
+     * ISO codes and flag emoji for a country name returned by the offline lookup.
+     * Returns null when the name is unknown to the loaded boundaries.
+     */
+    function getCountryMetadata(name) {
+        if (!name) return null;
+        const codes = (countryLookupInstance && countryLookupInstance.getCodes()[name]) || null;
+        const override = ISO_CODE_OVERRIDES[name];
+        if (!codes && !override) return null;
+        const iso2 = (override && override.iso2) || (codes && codes.iso2) || null;
+        const iso3 = (override && override.iso3) || (codes && codes.iso3) || null;
+        return { name, iso2, iso3, flag: flagEmoji(iso2) };
+    }
+
+    // Great-circle distance in meters between two lat/lng points
+    function haversineMeters(lat1, lng1, lat2, lng2) {
+        const toRad = Math.PI / 180;
+        const dLat = (lat2 - lat1) * toRad;
+        const dLng = (lng2 - lng1) * toRad;
+        const a = Math.sin(dLat / 2) ** 2 + Math.cos(lat1 * toRad) * Math.cos(lat2 * toRad) * Math.sin(dLng / 2) ** 2;
+        return 2 * EARTH_RADIUS_KM * 1000 * Math.asin(Math.min(1, Math.sqrt(a)));
     }
 
     /**
@@ -175,6 +428,68 @@
         return { lat, lng };
     }
 
+    // ===== LOCAL (VISIT TIME ZONE) DATES =====
+
+    // Timestamp carrying an explicit UTC offset, e.g. "2024-12-31T23:30:00.000-10:00"
+    const ISO_WITH_OFFSET_RE = /^(\d{4})-(\d{2})-(\d{2})T.*?([+-])(\d{2}):?(\d{2})$/;
+
+    /**
+     * Calendar date of a timestamp in the time zone it was recorded in. Timeline exports stamp
+     * each segment with its local UTC offset, so the date digits are already local; timestamps
+     * without an offset (or in UTC "Z") fall back to the browser's time zone.
+     * Returns { year, month (0-11), day } or null if unparseable.
+     */
+    function getLocalDateParts(isoString) {
+        if (!isoString || typeof isoString !== 'string') return null;
+        const match = ISO_WITH_OFFSET_RE.exec(isoString);
+        if (match) {
+            return { year: parseInt(match[1], 10), month: parseInt(match[2], 10) - 1, day: parseInt(match[3], 10) };
+        }
+        const date = new Date(isoString);
+        if (isNaN(date.getTime())) return null;
+        return { year: date.getFullYear(), month: date.getMonth(), day: date.getDate() };
+    }
+
+    // "YYYY-MM-DD" local day key for grouping
+    function getLocalDateKey(isoString) {
+        const parts = getLocalDateParts(isoString);
+        if (!parts) return null;
+        const pad = n => String(n).padStart(2, '0');
+        return `${parts.year}-${pad(parts.month + 1)}-${pad(parts.day)}`;
+    }
+
+    /**
+     * Format a timestamp's local calendar date (see getLocalDateParts) with Intl date options,
+     * so the day shown is the day it was recorded whatever the browser's time zone.
+     * Returns '' for missing or invalid timestamps.
+     */
+    function formatLocalDate(isoString, locale, options) {
+        const parts = getLocalDateParts(isoString);
+        if (!parts) return '';
+        const utcDate = new Date(Date.UTC(parts.year, parts.month, parts.day));
+        return utcDate.toLocaleDateString(locale, Object.assign({}, options, { timeZone: 'UTC' }));
+    }
+
+    /**
+     * Whether a timestamp's local calendar date falls in an inclusive "YYYY-MM-DD" range
+     * (either bound may be null for an open-ended range).
+     */
+    function isInLocalDateRange(isoString, from, to) {
+        const key = getLocalDateKey(isoString);
+        if (key === null) return false;
+        return (!from || key >= from) && (!to || key <= to);
+    }
+
+    // Add minutes to a timestamp, keeping its original UTC offset in the result
+    function addMinutesKeepingOffset(isoString, minutes) {
+        const ms = new Date(isoString).getTime() + minutes * 60 * 1000;
+        const match = ISO_WITH_OFFSET_RE.exec(isoString);
+        if (!match) return new Date(ms).toISOString();
+        const sign = match[4] === '-' ? -1 : 1;
+        const offsetMs = sign * (parseInt(match[5], 10) * 60 + parseInt(match[6], 10)) * 60 * 1000;
+        return new Date(ms + offsetMs).toISOString().replace('Z', `${match[4]}${match[5]}:${match[6]}`);
+    }
+
     /**
      * Get segments array from either Android/Web format ({ semanticSegments }) or iOS format (root array).
      */
@@ -202,18 +517,88 @@
         return placeLocation.latLng || null;
     }
 
+    /**
+     * Resolve the country for a visit segment: prefer the country stamped by processTimelineData, else look it up.
+     */
+    function getVisitCountry(segment) {
+        if (segment.country) return normalizeCountry(segment.country);
+        const placeLocation = segment.visit && segment.visit.topCandidate && segment.visit.topCandidate.placeLocation;
+        const parsed = parseLatLngString(getPlaceLatLngStr(placeLocation));
+        return parsed ? getCountryFromLatLng(parsed.lat, parsed.lng) : null;
+    }
+
+    // Key identifying a visit's place: its place ID, or its coordinates when the export has none
+    function getVisitPlaceKey(segment) {
+        const topCandidate = segment.visit.topCandidate || {};
+        return topCandidate.placeId || topCandidate.placeID || getPlaceLatLngStr(topCandidate.placeLocation);
+    }
+
+    // Timestamp string Date can parse (exports use ISO strings; anything else counts as missing)
+    const isValidTime = t => typeof t === 'string' && !isNaN(new Date(t).getTime());
+
+    /**
+     * Fill in missing segment timestamps (exports are in time order); recorded timestamps are never
+     * overwritten. A missing startTime takes the segment's own endTime when it has one, else the
+     * previous segment's end (or the next segment's start); a missing endTime takes the startTime.
+     * Filled segments are flagged with timeDerived: true, which keeps them out of speeds, records
+     * and the first/last date. Returns { derived, untimed } counts; untimed segments had nothing
+     * to derive from, or a recorded timestamp that is not an ISO string (left as is).
+     */
+    function backfillSegmentTimes(segments) {
+        let derived = 0;
+        let unusable = 0;      // segments with a recorded but unparseable timestamp
+        let lastKnown = null;  // end (or start) of the latest timed segment
+        let leading = [];      // untimed segments before the first timed one
+
+        const isMissing = t => t === undefined || t === null || t === '';
+        const fill = (segment, startTime) => {
+            segment.startTime = startTime;
+            segment.timeDerived = true;
+        };
+
+        segments.forEach(segment => {
+            if ([segment.startTime, segment.endTime].some(t => !isMissing(t) && !isValidTime(t))) {
+                unusable++;
+                return;
+            }
+            if (!isValidTime(segment.startTime)) {
+                if (isValidTime(segment.endTime)) {
+                    fill(segment, segment.endTime);
+                } else if (lastKnown === null) {
+                    leading.push(segment);
+                    return;
+                } else {
+                    fill(segment, lastKnown);
+                }
+            }
+            if (leading.length > 0) {
+                leading.forEach(s => { fill(s, segment.startTime); s.endTime = segment.startTime; derived++; });
+                leading = [];
+            }
+            if (!isValidTime(segment.endTime)) {
+                segment.endTime = segment.startTime;
+                segment.timeDerived = true;
+            }
+            if (segment.timeDerived) derived++;
+            lastKnown = segment.endTime;
+        });
+
+        return { derived, untimed: leading.length + unusable };
+    }
+
     // Process Google Timeline JSON (Android/Web semanticSegments or iOS root array)
     function processTimelineData(data) {
         const allSegments = getSegmentsFromData(data);
         const allLocations = [];
         const years = new Set();
+        const timeBackfill = backfillSegmentTimes(allSegments);
 
         // 1. Extract Locations and Years
         allSegments.forEach(segment => {
             // Collect Years
-            if (segment.startTime) {
-                const year = new Date(segment.startTime).getFullYear();
-                years.add(year);
+            const localDate = getLocalDateParts(segment.startTime);
+            if (localDate) {
+                years.add(localDate.year);
             }
 
             // Collect Locations for Map: visits (Android + iOS)
@@ -282,7 +667,9 @@
                     const parsed = parseLatLngString(typeof pointStr === 'string' ? pointStr : null);
                     if (parsed) {
                         const offsetMin = parseInt(point.durationMinutesOffsetFromStartTime, 10) || 0;
-                        const pointTime = new Date(segmentStart + offsetMin * 60 * 1000).toISOString();
+                        const pointTime = segment.startTime
+                            ? addMinutesKeepingOffset(segment.startTime, offsetMin)
+                            : new Date(segmentStart + offsetMin * 60 * 1000).toISOString();
                         allLocations.push({
                             lat: parsed.lat,
                             lng: parsed.lng,
@@ -301,7 +688,8 @@
         return {
             allSegments,
             allLocations,
-            years: Array.from(years)
+            years: Array.from(years),
+            timeBackfill
         };
     }
 
@@ -313,7 +701,8 @@
             countries: new Set(),
             transport: {}, // { type: { count, distanceMeters, durationMs } }
             visits: {},    // { placeId: { name, count, location, country } }
-            visitTypes: {} // { type: count } e.g. "Restaurant": 10
+            visitTypes: {}, // { type: count } e.g. "Restaurant": 10
+            countryScheme: getCountryScheme()
         };
 
         segments.forEach(segment => {
@@ -349,14 +738,7 @@
                     const placeId = visit.topCandidate.placeId || visit.topCandidate.placeID;
                     const latLngStr = getPlaceLatLngStr(placeLocation);
                     const name = (typeof placeLocation === 'object' && placeLocation.name) ? placeLocation.name : "Unknown Place";
-                    let country = segment.country || null;
-
-                    if (!country && latLngStr) {
-                        const parsed = parseLatLngString(latLngStr);
-                        if (parsed) {
-                            country = getCountryFromLatLng(parsed.lat, parsed.lng);
-                        }
-                    }
+                    const country = getVisitCountry(segment);
 
                     if (country) {
                         stats.countries.add(country);
@@ -382,7 +764,9 @@
         const stats = {
             eco: { totalCo2: 0, breakdown: {}, distanceByType: {} },
             time: { moving: 0, stationary: 0, total: 0 },
-            records: { longestDrive: 0, longestWalk: 0, maxVelocity: 0 }
+            records: { longestDrive: 0, longestWalk: 0, maxVelocity: 0 },
+            speeds: {},        // { type: { avgKmh, maxKmh, count } }
+            speedGlitches: []  // segments whose speed is impossible for their mode
         };
 
         // CO2 Emission Factors (approx g/km)
@@ -400,6 +784,23 @@
             'MOTORCYCLING': 100
         };
 
+        // Upper bound of plausible speed (km/h) per mode; anything faster is treated as a GPS glitch
+        const maxPlausibleKmh = {
+            'WALKING': 15,
+            'RUNNING': 30,
+            'CYCLING': 80,
+            'IN_BUS': 150,
+            'IN_SUBWAY': 150,
+            'IN_TRAM': 120,
+            'IN_TRAIN': 400,
+            'FLYING': 1100
+        };
+        const DEFAULT_MAX_PLAUSIBLE_KMH = 300;
+        // Very short segments give meaningless speeds
+        const MIN_SPEED_DURATION_MS = 60 * 1000;
+        const MIN_SPEED_DISTANCE_METERS = 100;
+        const speedTotals = {}; // { type: { distanceMeters, durationMs, maxKmh, count } }
+
         segments.forEach(segment => {
             const duration = new Date(segment.endTime) - new Date(segment.startTime);
             stats.time.total += duration;
@@ -418,6 +819,25 @@
                 stats.eco.totalCo2 += co2;
                 stats.eco.breakdown[type] = (stats.eco.breakdown[type] || 0) + co2;
 
+                if (segment.timeDerived) return;
+
+                if (distanceMeters >= MIN_SPEED_DISTANCE_METERS && duration >= MIN_SPEED_DURATION_MS) {
+                    const kmh = distanceKm / (duration / 3600000);
+                    if (kmh > (maxPlausibleKmh[type] || DEFAULT_MAX_PLAUSIBLE_KMH)) {
+                        stats.speedGlitches.push({ startTime: segment.startTime, type, kmh });
+                    } else {
+                        if (!speedTotals[type]) {
+                            speedTotals[type] = { distanceMeters: 0, durationMs: 0, maxKmh: 0, count: 0 };
+                        }
+                        const totals = speedTotals[type];
+                        totals.distanceMeters += distanceMeters;
+                        totals.durationMs += duration;
+                        totals.count++;
+                        if (kmh > totals.maxKmh) totals.maxKmh = kmh;
+                        if (kmh > stats.records.maxVelocity) stats.records.maxVelocity = kmh;
+                    }
+                }
+
                 if (distanceMeters) {
                     if ((type === 'IN_PASSENGER_VEHICLE' || type === 'IN_VEHICLE') && distanceMeters > stats.records.longestDrive) {
                         stats.records.longestDrive = distanceMeters;
@@ -432,14 +852,592 @@
             }
         });
 
+        Object.entries(speedTotals).forEach(([type, totals]) => {
+            stats.speeds[type] = {
+                avgKmh: (totals.distanceMeters / 1000) / (totals.durationMs / 3600000),
+                maxKmh: totals.maxKmh,
+                count: totals.count
+            };
+        });
+
         return stats;
     }
 
+    // Meteorological seasons, indexed by month 0-11
+    const SEASON_BY_MONTH = {
+        north: [
+            'Winter', 'Winter', 'Spring', 'Spring', 'Spring', 'Summer',
+            'Summer', 'Summer', 'Autumn', 'Autumn', 'Autumn', 'Winter'
+        ],
+        south: [
+            'Summer', 'Summer', 'Autumn', 'Autumn', 'Autumn', 'Winter',
+            'Winter', 'Winter', 'Spring', 'Spring', 'Spring', 'Summer'
+        ]
+    };
+
+    // Hemisphere most visits were in ('north' when there are no visit coordinates)
+    function getPredominantHemisphere(segments) {
+        let north = 0, south = 0;
+        segments.forEach(segment => {
+            if (!segment.visit) return;
+            const placeLocation = segment.visit.topCandidate && segment.visit.topCandidate.placeLocation;
+            const parsed = parseLatLngString(getPlaceLatLngStr(placeLocation));
+            if (!parsed) return;
+            if (parsed.lat < 0) south++;
+            else north++;
+        });
+        return south > north ? 'south' : 'north';
+    }
+
+    /**
+     * Break segments down by month and by season: unique places, visits, distance, and the countries
+     * first ever visited in each period, judged against the full history in allSegments (defaults to
+     * segments) like calculateRepeatVisits. Seasons follow the hemisphere most visits were in,
+     * returned as hemisphere ('north' | 'south').
+     */
+    function calculatePeriodBreakdown(segments, allSegments) {
+        const emptyPeriod = () => ({ places: 0, visits: 0, distanceMeters: 0, newCountries: [] });
+        const months = Array.from({ length: 12 }, emptyPeriod);
+        const seasons = { Winter: emptyPeriod(), Spring: emptyPeriod(), Summer: emptyPeriod(), Autumn: emptyPeriod() };
+        const placesByMonth = months.map(() => new Set());
+        const placesBySeason = { Winter: new Set(), Spring: new Set(), Summer: new Set(), Autumn: new Set() };
+        const hemisphere = getPredominantHemisphere(segments);
+
+        // Earliest visit segment per country across the full history
+        const firstVisit = {};
+        (allSegments || segments).forEach(segment => {
+            if (!segment.visit || !isValidTime(segment.startTime)) return;
+            const country = getVisitCountry(segment);
+            if (country && (!firstVisit[country] || new Date(segment.startTime) < new Date(firstVisit[country].startTime))) {
+                firstVisit[country] = segment;
+            }
+        });
+
+        const timed = segments
+            .filter(s => isValidTime(s.startTime))
+            .sort((a, b) => new Date(a.startTime) - new Date(b.startTime));
+
+        timed.forEach(segment => {
+            const month = getLocalDateParts(segment.startTime).month;
+            const season = SEASON_BY_MONTH[hemisphere][month];
+
+            if (segment.activity) {
+                const distanceMeters = Number(segment.activity.distanceMeters) || 0;
+                months[month].distanceMeters += distanceMeters;
+                seasons[season].distanceMeters += distanceMeters;
+            }
+
+            if (segment.visit) {
+                months[month].visits++;
+                seasons[season].visits++;
+
+                const placeKey = getVisitPlaceKey(segment);
+                if (placeKey) {
+                    placesByMonth[month].add(placeKey);
+                    placesBySeason[season].add(placeKey);
+                }
+
+                const country = getVisitCountry(segment);
+                if (country && firstVisit[country] === segment) {
+                    months[month].newCountries.push(country);
+                    seasons[season].newCountries.push(country);
+                }
+            }
+        });
+
+        months.forEach((m, i) => { m.places = placesByMonth[i].size; });
+        Object.keys(seasons).forEach(name => { seasons[name].places = placesBySeason[name].size; });
+
+        return { months, seasons, hemisphere };
+    }
+
+    /**
+     * Find notable extremes among visits: most northerly/southerly/easterly/westerly place,
+     * the earliest and latest timestamps in the given segments, and the longest trip away from
+     * home by days and by distance (see calculateTrips).
+     */
+    function calculateExtremes(segments) {
+        const extremes = {
+            north: null, south: null, east: null, west: null, firstDate: null, lastDate: null,
+            longestTripByDays: null, longestTripByDistance: null
+        };
+        let firstMs = Infinity, lastMs = -Infinity;
+
+        segments.forEach(segment => {
+            const startMs = segment.startTime && !segment.timeDerived ? new Date(segment.startTime).getTime() : NaN;
+            const endMs = segment.endTime && !segment.timeDerived ? new Date(segment.endTime).getTime() : startMs;
+            if (!isNaN(startMs) && startMs < firstMs) {
+                firstMs = startMs;
+                extremes.firstDate = segment.startTime;
+            }
+            if (!isNaN(endMs) && endMs > lastMs) {
+                lastMs = endMs;
+                extremes.lastDate = segment.endTime || segment.startTime;
+            }
+
+            if (!segment.visit || !segment.visit.topCandidate) return;
+            const placeLocation = segment.visit.topCandidate.placeLocation;
+            const parsed = parseLatLngString(getPlaceLatLngStr(placeLocation));
+            if (!parsed) return;
+
+            const point = {
+                lat: parsed.lat,
+                lng: parsed.lng,
+                name: (placeLocation && typeof placeLocation === 'object' && placeLocation.name) || null,
+                country: getVisitCountry(segment) || null,
+                date: segment.startTime || null
+            };
+            if (!extremes.north || point.lat > extremes.north.lat) extremes.north = point;
+            if (!extremes.south || point.lat < extremes.south.lat) extremes.south = point;
+            if (!extremes.east || point.lng > extremes.east.lng) extremes.east = point;
+            if (!extremes.west || point.lng < extremes.west.lng) extremes.west = point;
+        });
+
+        calculateTrips(segments).forEach(trip => {
+            if (!extremes.longestTripByDays || trip.days > extremes.longestTripByDays.days) {
+                extremes.longestTripByDays = trip;
+            }
+            if (trip.distanceMeters > 0 && (!extremes.longestTripByDistance || trip.distanceMeters > extremes.longestTripByDistance.distanceMeters)) {
+                extremes.longestTripByDistance = trip;
+            }
+        });
+
+        return extremes;
+    }
+
+    /**
+     * Split a year's countries and places into first-time vs repeat visits, judged against
+     * the full history in allSegments (a place counts as new in the year of its first visit).
+     */
+    function calculateRepeatVisits(allSegments, year) {
+        const firstCountryYear = {};
+        const firstPlaceYear = {};
+        const yearCountries = new Set();
+        const yearPlaces = new Set();
+
+        allSegments.forEach(segment => {
+            if (!segment.visit) return;
+            const localDate = getLocalDateParts(segment.startTime);
+            if (!localDate) return;
+            const visitYear = localDate.year;
+
+            const placeKey = getVisitPlaceKey(segment);
+            const country = getVisitCountry(segment);
+
+            if (country) {
+                if (!(country in firstCountryYear) || visitYear < firstCountryYear[country]) firstCountryYear[country] = visitYear;
+                if (visitYear === year) yearCountries.add(country);
+            }
+            if (placeKey) {
+                if (!(placeKey in firstPlaceYear) || visitYear < firstPlaceYear[placeKey]) firstPlaceYear[placeKey] = visitYear;
+                if (visitYear === year) yearPlaces.add(placeKey);
+            }
+        });
+
+        const newCountries = [], returningCountries = [];
+        yearCountries.forEach(c => (firstCountryYear[c] === year ? newCountries : returningCountries).push(c));
+        let newPlaces = 0, returningPlaces = 0;
+        yearPlaces.forEach(p => { if (firstPlaceYear[p] === year) newPlaces++; else returningPlaces++; });
+
+        return { newCountries, returningCountries, newPlaces, returningPlaces };
+    }
+
+    // Faster than any airliner over a meaningful distance: physically impossible for a phone to travel
+    const GLITCH_MAX_KMH = 1200;
+    const GLITCH_MIN_JUMP_METERS = 100000;
+
+    /**
+     * Find GPS glitches in locations: points that jump impossibly far from the previous point
+     * and impossibly far back to the next one (a "teleport" spike). Returns the indices into
+     * locations, with the implied speed, ordered by time.
+     */
+    function detectLocationGlitches(locations) {
+        const order = locations
+            .map((loc, index) => ({ loc, index, ms: loc.startTime ? new Date(loc.startTime).getTime() : NaN }))
+            .filter(p => !isNaN(p.ms))
+            .sort((a, b) => a.ms - b.ms);
+
+        const isImpossible = (a, b) => {
+            const meters = haversineMeters(a.loc.lat, a.loc.lng, b.loc.lat, b.loc.lng);
+            if (meters < GLITCH_MIN_JUMP_METERS) return false;
+            const hours = (b.ms - a.ms) / 3600000;
+            return hours <= 0 || (meters / 1000) / hours > GLITCH_MAX_KMH;
+        };
+
+        const glitches = [];
+        let prev = null;
+        order.forEach((point, i) => {
+            const next = order[i + 1] || null;
+            if (prev && isImpossible(prev, point) && (!next || (isImpossible(point, next) && !isImpossible(prev, next)))) {
+                const meters = haversineMeters(prev.loc.lat, prev.loc.lng, point.loc.lat, point.loc.lng);
+                const hours = (point.ms - prev.ms) / 3600000;
+                glitches.push({ index: point.index, startTime: point.loc.startTime, kmh: hours > 0 ? (meters / 1000) / hours : Infinity });
+                return;
+            }
+            prev = point;
+        });
+        return glitches;
+    }
+
+    /**
+     * Find periods with no location data at all: gaps between the end of covered time and the
+     * next segment's start lasting at least minGapDays. Returns [{ start, end, days }] in order.
+     */
+    function findCoverageGaps(segments, minGapDays = 2) {
+        const minGapMs = minGapDays * 24 * 60 * 60 * 1000;
+        const spans = segments
+            .filter(s => isValidTime(s.startTime))
+            .map(s => {
+                const start = new Date(s.startTime).getTime();
+                const hasEnd = isValidTime(s.endTime) && new Date(s.endTime).getTime() > start;
+                return { start, startTime: s.startTime, end: hasEnd ? new Date(s.endTime).getTime() : start, endTime: hasEnd ? s.endTime : s.startTime };
+            })
+            .sort((a, b) => a.start - b.start);
+
+        // Gap bounds are the neighbouring segments' own timestamps, keeping their UTC offsets
+        const gaps = [];
+        let covered = null; // { until (ms), time }
+        spans.forEach(span => {
+            if (covered !== null && span.start - covered.until >= minGapMs) {
+                gaps.push({
+                    start: covered.time,
+                    end: span.startTime,
+                    days: (span.start - covered.until) / (24 * 60 * 60 * 1000)
+                });
+            }
+            if (covered === null || span.end > covered.until) covered = { until: span.end, time: span.endTime };
+        });
+        return gaps;
+    }
+
+    const COUNTRY_MILESTONES = [1, 5, 10, 25, 50, 100];
+    const PLACE_MILESTONES = [100, 500, 1000, 5000];
+    const DISTANCE_MILESTONES_KM = [10000, 40075, 100000, 384400]; // 40,075 km = around the Earth, 384,400 km = to the Moon
+
+    /**
+     * Lifetime recap over the full history: the date each milestone was crossed
+     * (Nth country, Nth unique place, N km travelled, first trip abroad from the home country
+     * calculateTrips infers) and a per-decade breakdown.
+     */
+    function calculateLifetimeMilestones(allSegments) {
+        const milestones = [];
+        const decades = {};
+        const countries = new Set();
+        const places = new Set();
+        let distanceKm = 0;
+
+        const timed = allSegments
+            .filter(s => isValidTime(s.startTime))
+            .sort((a, b) => new Date(a.startTime) - new Date(b.startTime));
+
+        timed.forEach(segment => {
+            const date = segment.startTime;
+            const decadeKey = `${Math.floor(getLocalDateParts(date).year / 10) * 10}s`;
+            if (!decades[decadeKey]) {
+                decades[decadeKey] = { distanceMeters: 0, visits: 0, countries: new Set() };
+            }
+            const decade = decades[decadeKey];
+
+            if (segment.activity) {
+                const distanceMeters = Number(segment.activity.distanceMeters) || 0;
+                decade.distanceMeters += distanceMeters;
+                const before = distanceKm;
+                distanceKm += distanceMeters / 1000;
+                DISTANCE_MILESTONES_KM.forEach(km => {
+                    if (before < km && distanceKm >= km) milestones.push({ type: 'distance', count: km, date });
+                });
+            }
+
+            if (segment.visit) {
+                decade.visits++;
+                const country = getVisitCountry(segment);
+                if (country) {
+                    decade.countries.add(country);
+                    if (!countries.has(country)) {
+                        countries.add(country);
+                        if (COUNTRY_MILESTONES.includes(countries.size)) {
+                            milestones.push({ type: 'countries', count: countries.size, country, date });
+                        }
+                    }
+                }
+
+                const placeKey = getVisitPlaceKey(segment);
+                if (placeKey && !places.has(placeKey)) {
+                    places.add(placeKey);
+                    if (PLACE_MILESTONES.includes(places.size)) {
+                        milestones.push({ type: 'places', count: places.size, date });
+                    }
+                }
+            }
+        });
+
+        const trips = calculateTrips(allSegments);
+        if (trips.length > 0) {
+            milestones.push({ type: 'trips', count: 1, country: trips[0].countries[0], date: trips[0].start });
+            milestones.sort((a, b) => new Date(a.date) - new Date(b.date));
+        }
+
+        Object.values(decades).forEach(d => { d.countries = d.countries.size; });
+
+        return { milestones, decades };
+    }
+
+    /**
+     * For places visited in the given year that were also visited before it, the first visit
+     * that year and the most recent earlier visit. Keyed by placeId like calculateStats().visits.
+     */
+    function calculateRevisits(allSegments, year) {
+        const previous = {};  // placeId -> latest visit before the year { ms, time }
+        const thisYear = {};  // placeId -> first visit in the year { ms, time }
+
+        allSegments.forEach(segment => {
+            if (!segment.visit || !segment.visit.topCandidate || !isValidTime(segment.startTime)) return;
+            const placeId = segment.visit.topCandidate.placeId || segment.visit.topCandidate.placeID;
+            if (!placeId) return;
+            const visit = { ms: new Date(segment.startTime).getTime(), time: segment.startTime };
+
+            const visitYear = getLocalDateParts(segment.startTime).year;
+            if (visitYear < year) {
+                if (!(placeId in previous) || visit.ms > previous[placeId].ms) previous[placeId] = visit;
+            } else if (visitYear === year) {
+                if (!(placeId in thisYear) || visit.ms < thisYear[placeId].ms) thisYear[placeId] = visit;
+            }
+        });
+
+        const revisits = {};
+        Object.keys(thisYear).forEach(placeId => {
+            if (!(placeId in previous)) return;
+            revisits[placeId] = {
+                visitedAt: thisYear[placeId].time,
+                lastVisitedAt: previous[placeId].time,
+                daysSince: Math.floor((thisYear[placeId].ms - previous[placeId].ms) / (24 * 60 * 60 * 1000))
+            };
+        });
+        return revisits;
+    }
+
+    // Visits in the same country separated by less than this stay in one stay period
+    const COUNTRY_STAY_MAX_GAP_MS = 3 * 24 * 60 * 60 * 1000;
+
+    // Days since epoch of a timestamp's local calendar date (for counting calendar days)
+    function localDayNumber(isoString) {
+        const p = getLocalDateParts(isoString);
+        return Math.floor(Date.UTC(p.year, p.month, p.day) / (24 * 60 * 60 * 1000));
+    }
+
+    /**
+     * Per-country visit history: consecutive visits in a country are merged into stay periods
+     * ({ start, end, days }, days counted as local calendar days). Returns
+     * { country: { periods, trips, totalDays } } with periods in chronological order.
+     */
+    function calculateCountryHistory(segments) {
+        const visits = segments
+            .filter(s => s.visit && isValidTime(s.startTime))
+            .map(s => ({ segment: s, country: getVisitCountry(s) }))
+            .filter(v => v.country)
+            .sort((a, b) => new Date(a.segment.startTime) - new Date(b.segment.startTime));
+
+        const history = {};
+        let current = null;
+        const close = () => {
+            if (!current) return;
+            const days = localDayNumber(current.end) - localDayNumber(current.start) + 1;
+            if (!history[current.country]) history[current.country] = { periods: [], trips: 0, totalDays: 0 };
+            const entry = history[current.country];
+            entry.periods.push({ start: current.start, end: current.end, days });
+            entry.trips++;
+            entry.totalDays += days;
+        };
+
+        visits.forEach(({ segment, country }) => {
+            const start = segment.startTime;
+            const end = isValidTime(segment.endTime) ? segment.endTime : start;
+            if (current && current.country === country && new Date(start) - new Date(current.end) <= COUNTRY_STAY_MAX_GAP_MS) {
+                if (new Date(end) > new Date(current.end)) current.end = end;
+                return;
+            }
+            close();
+            current = { country, start, end };
+        });
+        close();
+
+        return history;
+    }
+
+    /**
+     * Trips away from home, built on calculateCountryHistory's stay periods: home is the country
+     * with the most days, and consecutive stays elsewhere less than COUNTRY_STAY_MAX_GAP_MS apart
+     * form one trip. A trip's distance and per-mode speeds (as in calculateAdvancedStats) count the
+     * activities between leaving home and getting back.
+     * Returns [{ start, end, days, distanceMeters, speeds, countries }] in chronological order.
+     */
+    function calculateTrips(segments) {
+        const history = calculateCountryHistory(segments);
+        const countries = Object.keys(history);
+        if (countries.length < 2) return [];
+        const home = countries.reduce((a, b) => (history[b].totalDays > history[a].totalDays ? b : a));
+
+        const periods = [];
+        countries.forEach(country => {
+            history[country].periods.forEach(period => periods.push(Object.assign({ country }, period)));
+        });
+        periods.sort((a, b) => new Date(a.start) - new Date(b.start));
+
+        const trips = [];
+        let current = null;
+        periods.forEach((period, i) => {
+            if (period.country === home) {
+                current = null;
+                return;
+            }
+            if (current && new Date(period.start) - new Date(current.end) <= COUNTRY_STAY_MAX_GAP_MS) {
+                if (new Date(period.end) > new Date(current.end)) current.end = period.end;
+                if (!current.countries.includes(period.country)) current.countries.push(period.country);
+            } else {
+                current = { start: period.start, end: period.end, countries: [period.country], leftMs: new Date(i > 0 ? periods[i - 1].end : period.start).getTime() };
+                trips.push(current);
+            }
+            const next = periods[i + 1];
+            current.backMs = new Date(next ? next.start : current.end).getTime();
+        });
+
+        const activities = segments
+            .filter(s => s.activity && isValidTime(s.startTime))
+            .map(s => ({ ms: new Date(s.startTime).getTime(), segment: s }));
+
+        return trips.map(trip => {
+            const tripActivities = activities
+                .filter(a => a.ms >= trip.leftMs && a.ms <= trip.backMs)
+                .map(a => a.segment);
+            return {
+                start: trip.start,
+                end: trip.end,
+                days: localDayNumber(trip.end) - localDayNumber(trip.start) + 1,
+                distanceMeters: tripActivities.reduce((sum, s) => sum + (Number(s.activity.distanceMeters) || 0), 0),
+                speeds: calculateAdvancedStats(tripActivities).speeds,
+                countries: trip.countries
+            };
+        });
+    }
+
+    const UN_MEMBER_COUNT = UN_MEMBER_STATES.length;
+
+    // UN member state a counted country belongs to (null if none), whichever scheme is active
+    function toUnMemberState(name) {
+        const mapped = COUNTRY_SCHEMES.un.mapping[name];
+        const member = mapped !== undefined ? mapped : name;
+        return UN_MEMBER_STATES.includes(member) ? member : null;
+    }
+
+    /**
+     * Share of the world visited: UN member states visited (territories folded in, as in the
+     * 'un' scheme) against all members, land area against the loaded country boundaries, and a
+     * headline percentage averaging the two. Area figures are null when no boundaries are loaded.
+     */
+    function calculateWorldCoverage(countries) {
+        const visited = Array.from(countries || []);
+        const membersVisited = new Set(visited.map(toUnMemberState).filter(Boolean)).size;
+        const countryPercent = (membersVisited / UN_MEMBER_COUNT) * 100;
+
+        let visitedAreaKm2 = null, totalAreaKm2 = null, areaPercent = null;
+        if (countryLookupInstance && countryLookupInstance.features.length > 0) {
+            const areas = countryLookupInstance.getAreas();
+            totalAreaKm2 = Object.values(areas).reduce((sum, a) => sum + a, 0);
+            visitedAreaKm2 = visited.reduce((sum, name) => sum + (areas[name] || 0), 0);
+            areaPercent = totalAreaKm2 > 0 ? (visitedAreaKm2 / totalAreaKm2) * 100 : null;
+        }
+
+        const worldPercent = areaPercent === null ? countryPercent : (countryPercent + areaPercent) / 2;
+
+        return {
+            countriesVisited: visited.length,
+            unMembersVisited: membersVisited,
+            unMemberCount: UN_MEMBER_COUNT,
+            countryPercent,
+            visitedAreaKm2,
+            totalAreaKm2,
+            areaPercent,
+            worldPercent,
+            methodology: areaPercent === null
+                ? `UN member states visited (territories counted under their member state) out of ${UN_MEMBER_COUNT}.`
+                : `Average of UN member states visited (territories counted under their member state) out of ${UN_MEMBER_COUNT} and visited land area out of all mapped country boundaries.`
+        };
+    }
+
+    // ===== SHARE IMAGE =====
+
+    // Share image rows showing each stat that HIDDEN_STATS (config.js) can hide
+    const SHARE_LABELS_BY_STAT = {
+        distance: ['Distance'],
+        countries: ['Countries'],
+        places: ['Unique places'],
+        records: ['Longest drive', 'Longest walk'],
+        eco: ['CO₂ reduced']
+    };
+
+    function isShareLabelHidden(label, hiddenStats) {
+        return Array.from(hiddenStats || []).some(key => (SHARE_LABELS_BY_STAT[key] || []).includes(label));
+    }
+
+    /**
+     * Stat rows ({ label, value }) for the share image from calculateStats / calculateAdvancedStats
+     * results, leaving out rows for hidden stats. options: { locale, hiddenStats }.
+     */
+    function buildShareStatRows(stats, advanced, options) {
+        const locale = options && options.locale;
+        const hiddenStats = options && options.hiddenStats;
+
+        const rows = [
+            { label: 'Distance', value: `${Math.round(stats.totalDistanceMeters / 1000).toLocaleString(locale)} km` },
+            { label: 'Countries', value: stats.countries.size.toLocaleString(locale) },
+            { label: 'Unique places', value: Object.keys(stats.visits || {}).length.toLocaleString(locale) }
+        ];
+
+        if (advanced && advanced.records) {
+            const oneDecimal = { minimumFractionDigits: 1, maximumFractionDigits: 1 };
+            const driveKm = (advanced.records.longestDrive / 1000).toLocaleString(locale, oneDecimal);
+            const walkKm = (advanced.records.longestWalk / 1000).toLocaleString(locale, oneDecimal);
+            if (advanced.records.longestDrive > 0) rows.push({ label: 'Longest drive', value: `${driveKm} km` });
+            if (advanced.records.longestWalk > 0) rows.push({ label: 'Longest walk', value: `${walkKm} km` });
+        }
+
+        if (advanced && advanced.eco) {
+            const distanceByType = advanced.eco.distanceByType || {};
+            const nonVehicleKm = (distanceByType.WALKING || 0) + (distanceByType.RUNNING || 0) + (distanceByType.CYCLING || 0);
+            const savedKg = Math.round((nonVehicleKm * 150) / 1000);
+            if (savedKg > 0) {
+                rows.push({ label: 'CO₂ reduced', value: `${savedKg.toLocaleString(locale)} kg` });
+            }
+        }
+
+        return rows.filter(row => !isShareLabelHidden(row.label, hiddenStats));
+    }
+
     exports.processTimelineData = processTimelineData;
     exports.calculateStats = calculateStats;
     exports.calculateAdvancedStats = calculateAdvancedStats;
+    exports.calculatePeriodBreakdown = calculatePeriodBreakdown;
+    exports.calculateExtremes = calculateExtremes;
+    exports.calculateWorldCoverage = calculateWorldCoverage;
+    exports.calculateRepeatVisits = calculateRepeatVisits;
+    exports.calculateLifetimeMilestones = calculateLifetimeMilestones;
+    exports.calculateRevisits = calculateRevisits;
+    exports.calculateCountryHistory = calculateCountryHistory;
+    exports.calculateTrips = calculateTrips;
+    exports.detectLocationGlitches = detectLocationGlitches;
+    exports.findCoverageGaps = findCoverageGaps;
+    exports.buildShareStatRows = buildShareStatRows;
+    exports.isShareLabelHidden = isShareLabelHidden;
     exports.setCountryGeoJSON = setCountryGeoJSON;
+    exports.setCountryScheme = setCountryScheme;
+    exports.getCountryScheme = getCountryScheme;
+    exports.getCountryMetadata = getCountryMetadata;
+    exports.UN_MEMBER_STATES = UN_MEMBER_STATES;
     exports.getSegmentsFromData = getSegmentsFromData;
+    exports.getLocalDateParts = getLocalDateParts;
+    exports.getLocalDateKey = getLocalDateKey;
+    exports.formatLocalDate = formatLocalDate;
+    exports.isInLocalDateRange = isInLocalDateRange;
     exports.Logger = Logger;
 
 })(typeof exports === 'undefined' ? (this.timelineUtils = {}) : exports);
@@ -5,11 +5,14 @@
 importScripts('https://cdn.jsdelivr.net/npm/rbush@3.0.1/rbush.min.js', 'timeline-utils.js');
 
 self.onmessage = function (e) {
-    const { jsonText, countryGeoJSON } = e.data || {};
+    const { jsonText, countryGeoJSON, countryScheme } = e.data || {};
     try {
         if (countryGeoJSON && typeof timelineUtils !== 'undefined' && timelineUtils.setCountryGeoJSON) {
             timelineUtils.setCountryGeoJSON(countryGeoJSON);
         }
+        if (countryScheme && typeof timelineUtils !== 'undefined' && timelineUtils.setCountryScheme) {
+            timelineUtils.setCountryScheme(countryScheme.name, countryScheme.aliases);
+        }
         const json = JSON.parse(jsonText);
         const segments = timelineUtils.getSegmentsFromData(json);
         if (!segments.length) {
@@ -22,13 +25,15 @@ self.onmessage = function (e) {
             allSegments: processed.allSegments,
             allLocations: processed.allLocations,
             years: processed.years,
+            timeBackfill: processed.timeBackfill,
             initialStats: {
                 totalDistanceMeters: initialStats.totalDistanceMeters,
                 totalVisits: initialStats.totalVisits,
                 countries: Array.from(initialStats.countries),
                 transport: initialStats.transport,
                 visits: initialStats.visits,
-                visitTypes: initialStats.visitTypes
+                visitTypes: initialStats.visitTypes,
+                countryScheme: initialStats.countryScheme
             }
         };
         self.postMessage(payload);